@@ -75,7 +75,9 @@ func HandleMock(ctx context.Context, p *server.Pipeline) error {
 	for _, word := range words {
 		select {
 		case <-ctx.Done():
-			return p.Done(ctx, event.StatusLost)
+			// Cancelled, archived or timed out tasks are reported by the worker itself,
+			// while tasks cut off by a shutdown are released for redelivery
+			return context.Cause(ctx)
 		default:
 			data := MockData{
 				Content: word + " ",
@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
+	"github.com/segmentio/kafka-go"
+)
+
+// BroadcastReader reads all partitions of a topic without any consumer group,
+// so that every reader receives every message.
+type BroadcastReader struct {
+	session *Session
+	logger  log.LogWrapper
+	topic   string
+
+	mutex    sync.Mutex
+	latest   bool
+	readers  []*kafka.Reader
+	messages chan kafka.Message
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newBroadcastReader(s *Session, topic string, latest bool) *BroadcastReader {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &BroadcastReader{
+		session:  s,
+		logger:   s.logger.Named("kafka/broadcast"),
+		topic:    topic,
+		latest:   latest,
+		messages: make(chan kafka.Message),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	// Offsets are resolved right away, so that a reader starting at the end receives
+	// every message written after it has been created
+	start, stop := context.WithTimeout(ctx, s.client.options.ConnectTimeout)
+	defer stop()
+
+	if err := r.start(start); err != nil {
+		r.logger.Debug("Deferring broadcast reader for topic '%s': %v", topic, err)
+	}
+
+	return r
+}
+
+// start creates a reader for every partition of the topic, unless it has already been started.
+func (r *BroadcastReader) start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.readers != nil {
+		return nil
+	}
+	if r.ctx.Err() != nil {
+		return fmt.Errorf("reader has already been closed")
+	}
+
+	conn, err := r.session.client.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("error during dial: %w", err)
+	}
+
+	partitions, err := conn.ReadPartitions(r.topic)
+	if errors.Is(err, kafka.UnknownTopicOrPartition) || (err == nil && len(partitions) == 0) {
+		// Messages of topics created afterwards have all been written after the reader
+		r.latest = false
+		return fmt.Errorf("topic '%s' does not exist yet", r.topic)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read partitions for topic '%s': %w", r.topic, err)
+	}
+
+	readers := make([]*kafka.Reader, 0, len(partitions))
+	for _, partition := range partitions {
+		reader, err := r.partition(ctx, partition.ID)
+		if err != nil {
+			for _, reader := range readers {
+				reader.Close()
+			}
+			return err
+		}
+		readers = append(readers, reader)
+	}
+
+	r.readers = readers
+	for _, reader := range readers {
+		go r.process(reader)
+	}
+
+	return nil
+}
+
+func (r *BroadcastReader) partition(ctx context.Context, partition int) (*kafka.Reader, error) {
+	options := r.session.client.options
+
+	leader, err := kafka.DialLeader(ctx, options.Network, options.Brokers[0], r.topic, partition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial leader for partition '%d': %w", partition, err)
+	}
+
+	first, last, err := leader.ReadOffsets()
+	leader.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offsets for partition '%d': %w", partition, err)
+	}
+
+	offset := first
+	if r.latest {
+		offset = last
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   options.Brokers,
+		Topic:     r.topic,
+		Partition: partition,
+		MaxWait:   options.MaxWait,
+		MinBytes:  int(options.MinBytes),
+		MaxBytes:  int(options.MaxBytes),
+	})
+
+	if err := reader.SetOffset(offset); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to set offset for partition '%d': %w", partition, err)
+	}
+
+	return reader, nil
+}
+
+// process forwards all messages of a single partition.
+func (r *BroadcastReader) process(reader *kafka.Reader) {
+	for {
+		msg, err := reader.FetchMessage(r.ctx)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+
+			r.logger.Warn("Error reading broadcast message: %v", err)
+			select {
+			case <-r.ctx.Done():
+				return
+
+			case <-time.After(time.Second * 2):
+				// Continue after a short delay
+			}
+			continue
+		}
+
+		select {
+		case r.messages <- msg:
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *BroadcastReader) ReadEvent(ctx context.Context, ev event.Event) error {
+	_, err := r.FetchEvent(ctx, ev)
+	return err
+}
+
+func (r *BroadcastReader) FetchEvent(ctx context.Context, ev event.Event) (transport.Message, error) {
+	if err := r.start(ctx); err != nil {
+		return transport.Message{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return transport.Message{}, fmt.Errorf("failed to fetch kafka message: %w", ctx.Err())
+
+	case <-r.ctx.Done():
+		return transport.Message{}, errors.New("reader has already been closed")
+
+	case msg := <-r.messages:
+		if err := r.session.Decode(message(msg), ev); err != nil {
+			return message(msg), &transport.DecodeError{
+				Message: message(msg),
+				Err:     err,
+			}
+		}
+
+		return message(msg), nil
+	}
+}
+
+// Commit does nothing, as broadcast readers do not belong to any consumer group.
+func (r *BroadcastReader) Commit(ctx context.Context, msgs ...transport.Message) error {
+	return nil
+}
+
+func (r *BroadcastReader) Close() error {
+	r.cancel()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var errs []error
+	for _, reader := range r.readers {
+		errs = append(errs, reader.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -10,9 +10,12 @@ import (
 	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
 	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 	"github.com/segmentio/kafka-go"
 )
 
+var _ transport.Transport = (*Client)(nil)
+
 type Client struct {
 	mutex    sync.RWMutex
 	logger   log.LogWrapper
@@ -28,7 +31,7 @@ func NewKafka(options options.ClientOptions, logger log.LogWrapper) (*Client, er
 	}, nil
 }
 
-func (c *Client) Session(suffix string) (*Session, error) {
+func (c *Client) Session(suffix string) (transport.Session, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
@@ -39,22 +42,22 @@ func (c *Client) Session(suffix string) (*Session, error) {
 		Suffix:   suffix,
 		client:   c,
 		logger:   c.logger.Named("kafka/session"),
-		readers:  make(map[string]*Reader),
+		readers:  make(map[string]transport.Reader),
 		writers:  make(map[string]*Writer),
 		cleanups: make([]func() error, 0),
 	}, nil
 }
 
 func (c *Client) Cleanup() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if len(c.cleanups) == 0 {
 		return nil
 	}
 
 	c.logger.Info("Performing kafka client cleanup")
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
 	var errs []error
 
 	for i, cleanup := range c.cleanups {
@@ -65,9 +68,18 @@ func (c *Client) Cleanup() error {
 		}
 	}
 
+	c.cleanups = nil
+	c.conn = nil
 	return errors.Join(errs...)
 }
 
+func (c *Client) addCleanup(cleanup func() error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cleanups = append(c.cleanups, cleanup)
+}
+
 func (c *Client) Marshal() ([]byte, error) {
 	return json.Marshal(c.options)
 }
@@ -79,10 +91,10 @@ func (c *Client) Unmarshal(data []byte) error {
 func (c *Client) dial(ctx context.Context) (*kafka.Conn, error) {
 	c.logger.Debug("Dialing kafka client connection")
 
-	if c.conn == nil {
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
+	if c.conn == nil {
 		conn, err := kafka.DialContext(ctx, c.options.Network, c.options.Brokers[0])
 		if err != nil {
 			return nil, err
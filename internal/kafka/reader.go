@@ -6,6 +6,7 @@ import (
 
 	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -28,5 +29,74 @@ func (r *Reader) ReadEvent(ctx context.Context, ev event.Event) error {
 
 	r.logger.Debug("New kafka event read with key '%s'", string(msg.Key))
 
-	return ev.Unmarshal(msg.Value)
+	if err := r.session.Decode(message(msg), ev); err != nil {
+		return &transport.DecodeError{
+			Message: message(msg),
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (r *Reader) FetchEvent(ctx context.Context, ev event.Event) (transport.Message, error) {
+	r.logger.Info("Fetching new kafka event...")
+
+	msg, err := r.reader.FetchMessage(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to fetch kafka message: %w", err)
+
+		r.logger.Error("%v", err)
+		return transport.Message{}, err
+	}
+
+	r.logger.Debug("New kafka event fetched with key '%s'", string(msg.Key))
+
+	if err := r.session.Decode(message(msg), ev); err != nil {
+		return message(msg), &transport.DecodeError{
+			Message: message(msg),
+			Err:     err,
+		}
+	}
+
+	return message(msg), nil
+}
+
+func (r *Reader) Commit(ctx context.Context, msgs ...transport.Message) error {
+	// Offsets can only be committed for readers within a consumer group
+	if r.reader.Config().GroupID == "" {
+		return nil
+	}
+
+	commits := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		commits = append(commits, kafka.Message{
+			Topic:     msg.Topic,
+			Partition: msg.Partition,
+			Offset:    msg.Offset,
+		})
+	}
+
+	if err := r.reader.CommitMessages(ctx, commits...); err != nil {
+		return fmt.Errorf("failed to commit kafka messages: %w", err)
+	}
+
+	return nil
+}
+
+func message(msg kafka.Message) transport.Message {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, header := range msg.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+
+	return transport.Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     msg.Value,
+		Headers:   headers,
+		Time:      msg.Time,
+	}
 }
@@ -3,11 +3,14 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
+	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
 	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -19,13 +22,17 @@ type Session struct {
 	client *Client
 	logger log.LogWrapper
 
-	readers  map[string]*Reader
+	readers  map[string]transport.Reader
 	writers  map[string]*Writer
 	cleanups []func() error
 }
 
-func (s *Session) Client() *Client {
-	return s.client
+func (s *Session) GetID() string {
+	return s.ID
+}
+
+func (s *Session) GetSuffix() string {
+	return s.Suffix
 }
 
 func (s *Session) CreateTopic(ctx context.Context, topic string, opts ...options.TopicOption) error {
@@ -62,21 +69,45 @@ func (s *Session) CreateTopic(ctx context.Context, topic string, opts ...options
 	return conn.CreateTopics(config)
 }
 
-func (s *Session) GetReader(suffix string) *Reader {
+func (s *Session) GetReader(suffix string, opts ...options.ReaderOption) transport.Reader {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	if reader, exist := s.readers[suffix]; exist {
+	options := options.ReaderOptions{
+		GroupID: s.client.options.GroupID,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	key := suffix + "/" + options.GroupID
+	if options.Broadcast {
+		options.GroupID = ""
+		key = suffix + "/broadcast"
+	}
+
+	if reader, exist := s.readers[key]; exist {
 		s.logger.Debug("Returning existing reader for suffix '%s'", suffix)
 		return reader
 	}
 
+	if options.Broadcast {
+		reader := newBroadcastReader(s, s.fullTopic(suffix), options.Latest)
+
+		s.logger.Debug("Creating new broadcast reader for suffix '%s'", suffix)
+
+		s.readers[key] = reader
+		s.client.addCleanup(reader.Close)
+
+		return reader
+	}
+
 	reader := &Reader{
 		session: s,
 		reader: kafka.NewReader(kafka.ReaderConfig{
 			Brokers:          s.client.options.Brokers,
 			Topic:            s.fullTopic(suffix),
-			GroupID:          s.client.options.GroupID,
+			GroupID:          options.GroupID,
 			MaxWait:          s.client.options.MaxWait,
 			CommitInterval:   s.client.options.CommitInterval,
 			MinBytes:         int(s.client.options.MinBytes),
@@ -88,13 +119,13 @@ func (s *Session) GetReader(suffix string) *Reader {
 
 	s.logger.Debug("Creating new reader for suffix '%s'", suffix)
 
-	s.readers[suffix] = reader
-	s.client.cleanups = append(s.cleanups, reader.reader.Close)
+	s.readers[key] = reader
+	s.client.addCleanup(reader.reader.Close)
 
 	return reader
 }
 
-func (s *Session) GetWriter(suffix string) *Writer {
+func (s *Session) GetWriter(suffix string) transport.Writer {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
@@ -103,12 +134,13 @@ func (s *Session) GetWriter(suffix string) *Writer {
 		return writer
 	}
 
+	// Events are keyed by their task, so all events of a task are kept in order on the same partition
 	writer := &Writer{
 		session: s,
 		writer: &kafka.Writer{
 			Addr:         kafka.TCP(s.client.options.Brokers...),
 			Topic:        s.fullTopic(suffix),
-			Balancer:     &kafka.LeastBytes{},
+			Balancer:     &kafka.Hash{},
 			BatchSize:    s.client.options.BatchSize,
 			BatchTimeout: s.client.options.BatchTimeout,
 			BatchBytes:   s.client.options.BatchBytes,
@@ -120,7 +152,7 @@ func (s *Session) GetWriter(suffix string) *Writer {
 	s.logger.Debug("Creating new writer for suffix '%s'", suffix)
 
 	s.writers[suffix] = writer
-	s.client.cleanups = append(s.cleanups, writer.writer.Close)
+	s.client.addCleanup(writer.writer.Close)
 
 	return writer
 }
@@ -141,3 +173,112 @@ func (s *Session) fullTopic(suffix string) string {
 
 	return text.String()
 }
+
+func (s *Session) Decode(msg transport.Message, ev event.Event) error {
+	return transport.Decode(s.client.options.Codecs, msg, ev)
+}
+
+func (s *Session) Scan(ctx context.Context, topic string, fn func(transport.Message) error) error {
+	conn, err := s.client.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("error during dial: %w", err)
+	}
+
+	full := s.fullTopic(topic)
+
+	partitions, err := conn.ReadPartitions(full)
+	if err != nil {
+		return fmt.Errorf("failed to read partitions for topic '%s': %w", full, err)
+	}
+
+	s.logger.Debug("Scanning '%d' partitions for topic '%s'", len(partitions), full)
+
+	for _, partition := range partitions {
+		if err := s.scanPartition(ctx, full, partition.ID, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) ScanKey(ctx context.Context, topic string, key string, fn func(transport.Message) error) error {
+	conn, err := s.client.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("error during dial: %w", err)
+	}
+
+	full := s.fullTopic(topic)
+
+	partitions, err := conn.ReadPartitions(full)
+	if err != nil {
+		return fmt.Errorf("failed to read partitions for topic '%s': %w", full, err)
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(partitions))
+	for _, partition := range partitions {
+		ids = append(ids, partition.ID)
+	}
+	slices.Sort(ids)
+
+	// Writers balance with the same hash across the sorted partitions of the topic
+	partition := (&kafka.Hash{}).Balance(kafka.Message{Key: []byte(key)}, ids...)
+
+	s.logger.Debug("Scanning partition '%d' of topic '%s' for key '%s'", partition, full, key)
+
+	return s.scanPartition(ctx, full, partition, func(msg transport.Message) error {
+		if msg.Key != key {
+			return nil
+		}
+		return fn(msg)
+	})
+}
+
+func (s *Session) scanPartition(ctx context.Context, topic string, partition int, fn func(transport.Message) error) error {
+	leader, err := kafka.DialLeader(ctx, s.client.options.Network, s.client.options.Brokers[0], topic, partition)
+	if err != nil {
+		return fmt.Errorf("failed to dial leader for partition '%d': %w", partition, err)
+	}
+
+	first, last, err := leader.ReadOffsets()
+	leader.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to read offsets for partition '%d': %w", partition, err)
+	}
+	if last <= first {
+		return nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   s.client.options.Brokers,
+		Topic:     topic,
+		Partition: partition,
+		MaxWait:   s.client.options.MaxWait,
+		MinBytes:  int(s.client.options.MinBytes),
+		MaxBytes:  int(s.client.options.MaxBytes),
+	})
+	defer reader.Close()
+
+	if err := reader.SetOffset(first); err != nil {
+		return fmt.Errorf("failed to set offset for partition '%d': %w", partition, err)
+	}
+
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read kafka message: %w", err)
+		}
+
+		if err := fn(message(msg)); err != nil {
+			return err
+		}
+
+		if msg.Offset >= last-1 {
+			return nil
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -22,7 +23,9 @@ func (w *Writer) WriteEvent(ctx context.Context, ev event.Event) error {
 	key := ev.GetID()
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 
-	value, err := ev.Marshal()
+	contentType := w.session.client.options.Codec
+
+	value, err := transport.Encode(w.session.client.options.Codecs, contentType, ev)
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
@@ -41,6 +44,10 @@ func (w *Writer) WriteEvent(ctx context.Context, ev event.Event) error {
 				Key:   "timestamp",
 				Value: []byte(timestamp),
 			},
+			{
+				Key:   transport.HeaderContentType,
+				Value: []byte(contentType),
+			},
 		},
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -11,27 +12,42 @@ import (
 
 	"github.com/mwantia/asynk/internal/kafka"
 	basic "github.com/mwantia/asynk/internal/log"
+	"github.com/mwantia/asynk/pkg/codec"
 	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
 	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
 type Client struct {
 	suffix string
 
-	options options.ClientOptions
-	logger  log.LogWrapper
-	session *kafka.Session
-	events  map[string]chan *event.StatusEvent
-
-	mutex  sync.RWMutex
-	active atomic.Bool
-	ctx    context.Context
-	cancel context.CancelFunc
-	wait   sync.WaitGroup
+	options   options.ClientOptions
+	logger    log.LogWrapper
+	transport transport.Transport
+	session   transport.Session
+	events    map[string][]*subscription
+
+	submitInterceptors []SubmitInterceptor
+	statusInterceptors []StatusInterceptor
+
+	mutex    sync.RWMutex
+	dispatch sync.Once
+	unique   sync.Once
+	uniques  *uniqueTable
+	active   atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wait     sync.WaitGroup
 }
 
 func NewClient(suffix string, opts ...options.ClientOption) (*Client, error) {
+	return NewClientWithTransport(suffix, nil, opts...)
+}
+
+// NewClientWithTransport creates a client using the provided transport.
+// If no transport is provided, a kafka transport is created from the options.
+func NewClientWithTransport(suffix string, t transport.Transport, opts ...options.ClientOption) (*Client, error) {
 	suffix = strings.TrimSpace(suffix)
 	if suffix == "" {
 		return nil, errors.New("suffix cannot be empty")
@@ -44,6 +60,10 @@ func NewClient(suffix string, opts ...options.ClientOption) (*Client, error) {
 		}
 	}
 
+	if err := transport.ValidateCodec(options.Codecs, options.Codec); err != nil {
+		return nil, fmt.Errorf("invalid codec: %w", err)
+	}
+
 	var logger log.LogWrapper
 
 	if options.Logger != nil {
@@ -54,14 +74,17 @@ func NewClient(suffix string, opts ...options.ClientOption) (*Client, error) {
 		logger = l.Named("asynk/client")
 	}
 
-	k, err := kafka.NewKafka(options, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	if t == nil {
+		k, err := kafka.NewKafka(options, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka client: %w", err)
+		}
+		t = k
 	}
 
-	s, err := k.Session(suffix)
+	s, err := t.Session(suffix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka session: %w", err)
+		return nil, fmt.Errorf("failed to create transport session: %w", err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -69,15 +92,22 @@ func NewClient(suffix string, opts ...options.ClientOption) (*Client, error) {
 	c := &Client{
 		suffix: suffix,
 
-		options: options,
-		logger:  logger,
-		session: s,
-		events:  make(map[string]chan *event.StatusEvent),
+		options:   options,
+		logger:    logger,
+		transport: t,
+		session:   s,
+		events:    make(map[string][]*subscription),
+		uniques:   newUniqueTable(),
 
 		ctx:    ctx,
 		cancel: cancel,
 	}
 
+	if options.Store != nil {
+		// Start the status dispatcher immediately to record all tasks
+		c.startDispatcher()
+	}
+
 	c.active.Store(true)
 	return c, nil
 }
@@ -87,25 +117,88 @@ func (c *Client) Archive(ctx context.Context, ev *event.SubmitEvent, reason stri
 		return fmt.Errorf("client has already been closed")
 	}
 
-	return errors.New("not implemented")
+	if ev == nil || ev.ID == "" {
+		return errors.New("submit event must have a valid id")
+	}
+
+	c.logger.Info("Archiving task '%s'", ev.ID)
+
+	writer := c.session.GetWriter("events.control")
+
+	if err := writer.WriteEvent(ctx, &event.ControlEvent{
+		ID:     ev.ID,
+		Time:   time.Now(),
+		Action: event.ActionArchive,
+		Reason: reason,
+	}); err != nil {
+		return fmt.Errorf("failed to write control event: %w", err)
+	}
+
+	return nil
 }
 
-func (c *Client) Submit(ctx context.Context, ev event.SubmitEvent) (chan *event.StatusEvent, error) {
+// Cancel requests the cancellation of the task with the given ID.
+// A running task has its context cancelled, a queued task is skipped by the workers.
+func (c *Client) Cancel(ctx context.Context, id string) error {
 	if !c.active.Load() {
-		return nil, fmt.Errorf("client has already been closed")
+		return fmt.Errorf("client has already been closed")
 	}
 
-	c.logger.Info("Submitting task '%s' to Kafka", ev.ID)
+	if strings.TrimSpace(id) == "" {
+		return errors.New("id cannot be empty")
+	}
 
-	ctx, cancel := context.WithCancel(ctx)
+	c.logger.Info("Cancelling task '%s'", id)
 
-	go func() {
-		select {
-		case <-c.ctx.Done():
-			cancel()
-		case <-ctx.Done():
+	writer := c.session.GetWriter("events.control")
+
+	if err := writer.WriteEvent(ctx, &event.ControlEvent{
+		ID:     id,
+		Time:   time.Now(),
+		Action: event.ActionCancel,
+	}); err != nil {
+		return fmt.Errorf("failed to write control event: %w", err)
+	}
+
+	return nil
+}
+
+// Submit passes the task through all submit interceptors before it is written to its topic.
+func (c *Client) Submit(ctx context.Context, ev event.SubmitEvent, opts ...options.SubmitOption) (chan *event.StatusEvent, error) {
+	submit := SubmitFunc(c.submit)
+
+	c.mutex.RLock()
+	for i := len(c.submitInterceptors) - 1; i >= 0; i-- {
+		submit = c.submitInterceptors[i](submit)
+	}
+	c.mutex.RUnlock()
+
+	return submit(ctx, ev, opts...)
+}
+
+// submit writes the task to its topic, after all submit interceptors have been called.
+func (c *Client) submit(ctx context.Context, ev event.SubmitEvent, opts ...options.SubmitOption) (chan *event.StatusEvent, error) {
+	if !c.active.Load() {
+		return nil, fmt.Errorf("client has already been closed")
+	}
+
+	submitOptions := options.DefaultSubmitOptions()
+	for _, opt := range opts {
+		if err := opt(&submitOptions); err != nil {
+			return nil, fmt.Errorf("failed to apply submit option: %w", err)
 		}
-	}()
+	}
+
+	c.logger.Info("Submitting task '%s'", ev.ID)
+
+	if !ev.Priority.IsValid() {
+		return nil, fmt.Errorf("invalid priority '%s'", ev.Priority)
+	}
+
+	if len(ev.Payload) > 0 && !codec.IsJSON(ev.ContentType) && codec.IsJSON(c.options.Codec) {
+		// Binary payloads cannot be embedded into json events without being encoded again
+		return nil, fmt.Errorf("payload with content type '%s' requires a binary codec", ev.ContentType)
+	}
 
 	if ev.Time.IsZero() {
 		now := time.Now()
@@ -119,27 +212,65 @@ func (c *Client) Submit(ctx context.Context, ev event.SubmitEvent) (chan *event.
 		ev.ID = id
 	}
 
-	writer := c.session.GetWriter("events.submit")
+	if key := submitOptions.UniqueKey; key != "" {
+		holder, err := c.claim(ctx, key, ev.ID, submitOptions.UniqueTTL)
+		if err != nil {
+			return nil, err
+		}
+
+		if holder != ev.ID {
+			if submitOptions.UniqueMode == options.UniqueReject {
+				return nil, &DuplicateError{Key: key, ID: holder}
+			}
 
-	if err := writer.WriteEvent(ctx, &ev); err != nil {
-		return nil, fmt.Errorf("failed to write kafka message: %w", err)
+			c.logger.Info("Unique key '%s' is held by task '%s'; Collapsing into existing task", key, holder)
+			// The result of a finished task is only retained on the status topic
+			return c.follow(ctx, holder, c.latest)
+		}
+
+		ev.Metadata = maps.Clone(ev.Metadata)
+		if ev.Metadata == nil {
+			ev.Metadata = event.Metadata{}
+		}
+		ev.Metadata[event.MetadataUniqueKey] = key
 	}
 
-	reader := c.session.GetReader("events.status")
+	// Subscribe before writing to never miss any status update
+	sub, err := c.subscribe(ctx, ev.ID, false)
+	if err != nil {
+		return nil, err
+	}
 
-	c.logger.Debug("Creating status channel for task '%s'", ev.ID)
+	topic := ev.Priority.Topic()
+	if !ev.IsDue(time.Now()) {
+		c.logger.Debug("Task '%s' is delayed until '%v'", ev.ID, ev.NotBefore)
+		topic = "events.delayed"
+	}
 
-	c.mutex.Lock()
-	ch := make(chan *event.StatusEvent, 100)
-	c.events[ev.ID] = ch
-	c.mutex.Unlock()
+	writer := c.session.GetWriter(topic)
 
-	c.wait.Add(1)
+	if err := writer.WriteEvent(ctx, &ev); err != nil {
+		c.close(sub)
+		if key := submitOptions.UniqueKey; key != "" {
+			c.release(context.WithoutCancel(ctx), key, ev.ID)
+		}
+		return nil, fmt.Errorf("failed to write submit event: %w", err)
+	}
 
-	c.logger.Debug("Started event processing goroutine for task '%s'", ev.ID)
-	go c.processEvent(ctx, ev.ID, reader, ch)
+	c.recordPending(ctx, &ev)
+
+	return sub.ch, nil
+}
 
-	return ch, nil
+// SubmitAt submits the task, which will not be processed before the given time.
+func (c *Client) SubmitAt(ctx context.Context, at time.Time, ev event.SubmitEvent, opts ...options.SubmitOption) (chan *event.StatusEvent, error) {
+	ev.NotBefore = at
+	return c.Submit(ctx, ev, opts...)
+}
+
+// SubmitIn submits the task, which will not be processed before the delay has passed.
+func (c *Client) SubmitIn(ctx context.Context, delay time.Duration, ev event.SubmitEvent, opts ...options.SubmitOption) (chan *event.StatusEvent, error) {
+	return c.SubmitAt(ctx, time.Now().Add(delay), ev, opts...)
 }
 
 func (c *Client) Close() error {
@@ -151,6 +282,18 @@ func (c *Client) Close() error {
 
 	c.cancel()
 
+	c.mutex.Lock()
+	subs := make([]*subscription, 0, len(c.events))
+	for _, list := range c.events {
+		subs = append(subs, list...)
+	}
+	c.mutex.Unlock()
+
+	for _, sub := range subs {
+		c.logger.Debug("Closing channel for task '%s'", sub.id)
+		c.close(sub)
+	}
+
 	done := make(chan struct{})
 	go func() {
 		c.wait.Wait()
@@ -164,13 +307,5 @@ func (c *Client) Close() error {
 		c.logger.Warn("Shutdown timed out; Some goroutines may still be running...")
 	}
 
-	c.mutex.Lock()
-	for id, channel := range c.events {
-		c.logger.Debug("Closing channel for task '%s'", id)
-		close(channel)
-	}
-	c.events = make(map[string]chan *event.StatusEvent)
-	c.mutex.Unlock()
-
-	return c.session.Client().Cleanup()
+	return c.transport.Cleanup()
 }
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// ListDeadLetters returns all tasks currently retained on the dead-letter topic.
+func (c *Client) ListDeadLetters(ctx context.Context) ([]*event.DeadEvent, error) {
+	if !c.active.Load() {
+		return nil, fmt.Errorf("client has already been closed")
+	}
+
+	evs := make([]*event.DeadEvent, 0)
+
+	if err := c.session.Scan(ctx, "events.dead", func(msg transport.Message) error {
+		ev := &event.DeadEvent{}
+		if err := c.session.Decode(msg, ev); err != nil {
+			c.logger.Warn("Skipping invalid dead-letter event '%s': %v", msg.Key, err)
+			return nil
+		}
+
+		evs = append(evs, ev)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to scan dead-letter topic: %w", err)
+	}
+
+	return evs, nil
+}
+
+// ReplayDeadLetter submits the latest dead-lettered task with the given ID again.
+// The retry count and attempt history are reset and the task is submitted with a new time,
+// so that it is not skipped by workers remembering the task as processed.
+func (c *Client) ReplayDeadLetter(ctx context.Context, id string) (chan *event.StatusEvent, error) {
+	evs, err := c.ListDeadLetters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dead *event.DeadEvent
+	for _, ev := range evs {
+		if ev.ID == id {
+			dead = ev
+		}
+	}
+
+	if dead == nil {
+		return nil, fmt.Errorf("dead-letter task '%s' not found", id)
+	}
+	if dead.Submit == nil {
+		return nil, fmt.Errorf("dead-letter task '%s' could not be decoded and cannot be replayed", id)
+	}
+
+	c.logger.Info("Replaying dead-letter task '%s'", id)
+
+	submit := *dead.Submit
+	submit.Time = time.Now()
+	submit.Attempts = nil
+	submit.Metadata = event.Metadata{}
+	for key, value := range dead.Submit.Metadata {
+		switch key {
+		case event.MetadataRetryCount, event.MetadataLastError, event.MetadataLastAttempt:
+			continue
+		}
+		submit.Metadata[key] = value
+	}
+
+	return c.Submit(ctx, submit)
+}
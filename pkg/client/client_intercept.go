@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+)
+
+// SubmitFunc submits a task and returns the channel receiving its status updates.
+type SubmitFunc func(ctx context.Context, ev event.SubmitEvent, opts ...options.SubmitOption) (chan *event.StatusEvent, error)
+
+// SubmitInterceptor wraps every submit, e.g. to add metadata or to validate tasks before calling next.
+type SubmitInterceptor func(next SubmitFunc) SubmitFunc
+
+// DeliverFunc delivers a status event to the channels of its task.
+type DeliverFunc func(ev *event.StatusEvent)
+
+// StatusInterceptor wraps the delivery of every status event. Events can be changed before calling next,
+// or dropped by not calling next at all. Interceptors are called by the status dispatcher and must not block.
+type StatusInterceptor func(next DeliverFunc) DeliverFunc
+
+// UseSubmit adds interceptors around Submit. The first interceptor is the outermost one.
+func (c *Client) UseSubmit(interceptors ...SubmitInterceptor) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.submitInterceptors = append(c.submitInterceptors, interceptors...)
+}
+
+// UseStatus adds interceptors around the delivery of status events. The first interceptor is the outermost one.
+func (c *Client) UseStatus(interceptors ...StatusInterceptor) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.statusInterceptors = append(c.statusInterceptors, interceptors...)
+}
+
+// intercept passes the status event through all status interceptors before delivering it.
+func (c *Client) intercept(ev *event.StatusEvent, deliver DeliverFunc) {
+	c.mutex.RLock()
+	for i := len(c.statusInterceptors) - 1; i >= 0; i-- {
+		deliver = c.statusInterceptors[i](deliver)
+	}
+	c.mutex.RUnlock()
+
+	deliver(ev)
+}
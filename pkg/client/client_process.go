@@ -2,55 +2,108 @@ package client
 
 import (
 	"context"
+	"slices"
 	"time"
 
-	"github.com/mwantia/asynk/internal/kafka"
 	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
-func (c *Client) processEvent(ctx context.Context, id string, reader *kafka.Reader, ch chan *event.StatusEvent) {
-	lctx, cancel := context.WithCancel(c.ctx)
+// subscribe registers a new subscription for the task and starts the dispatcher if required.
+// Paused subscriptions only queue events until they are resumed with the replayed history.
+func (c *Client) subscribe(ctx context.Context, id string, paused bool) (*subscription, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-	c.logger.Debug("Starting event processing for task '%s'", id)
+	c.logger.Debug("Creating status subscription for task '%s'", id)
 
-	defer c.wait.Done()
-	defer cancel()
+	sub := newSubscription(id, 100, paused)
+	c.events[id] = append(c.events[id], sub)
 
-	defer func() {
-		c.mutex.Lock()
-		if _, exist := c.events[id]; exist {
-			c.logger.Debug("Closing channel for task '%s'", id)
+	sub.release = context.AfterFunc(ctx, func() {
+		c.logger.Debug("Context for task '%s' was cancelled", id)
+		c.close(sub)
+	})
 
-			delete(c.events, id)
-			close(ch)
-		}
-		c.mutex.Unlock()
-	}()
+	c.startDispatcher()
+
+	return sub, nil
+}
+
+// startDispatcher starts the status dispatcher, unless it is already running. The status reader is created
+// before returning, so that no status of a task written afterwards is missed.
+func (c *Client) startDispatcher() {
+	c.dispatch.Do(func() {
+		reader := c.statusReader()
+
+		c.wait.Add(1)
+		go c.processStatus(reader)
+	})
+}
+
+// statusReader reads all status events written from now on, unless a status group has been configured,
+// in which case reading resumes from the last offset committed by the group.
+func (c *Client) statusReader() transport.Reader {
+	if c.options.StatusGroupID != "" {
+		return c.session.GetReader("events.status", options.WithReaderGroupID(c.options.StatusGroupID))
+	}
+
+	return c.session.GetReader("events.status", options.WithBroadcast(), options.WithLatest())
+}
+
+// unsubscribe removes the subscription, if it is still registered.
+func (c *Client) unsubscribe(sub *subscription) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	subs := slices.DeleteFunc(c.events[sub.id], func(s *subscription) bool {
+		return s == sub
+	})
+
+	if len(subs) == 0 {
+		delete(c.events, sub.id)
+	} else {
+		c.events[sub.id] = subs
+	}
+
+	if sub.release != nil {
+		sub.release()
+	}
+}
+
+// close closes the subscription channel and removes the subscription.
+func (c *Client) close(sub *subscription) {
+	sub.mutex.Lock()
+	sub.close()
+	sub.mutex.Unlock()
+
+	c.unsubscribe(sub)
+}
+
+// processStatus is the single status dispatcher of the client. It reads the status topic once
+// and routes every status event to the subscription registered for its task.
+func (c *Client) processStatus(reader transport.Reader) {
+	defer c.wait.Done()
+
+	c.logger.Debug("Starting status dispatcher")
 
 	for {
 		select {
-		case <-lctx.Done():
-			c.logger.Debug("Kafka reader for task '%s' was cancelled", id)
-			return
-
-		case <-ctx.Done():
-			c.logger.Debug("Context for task '%s' was cancelled", id)
+		case <-c.ctx.Done():
+			c.logger.Debug("Status dispatcher stopped")
 			return
 
 		default:
 			evs := &event.StatusEvent{}
-			if err := reader.ReadEvent(ctx, evs); err != nil {
-				if ctx.Err() != nil {
+			if err := reader.ReadEvent(c.ctx, evs); err != nil {
+				if c.ctx.Err() != nil {
 					return
 				}
 
 				c.logger.Warn("Error reading status event: %v", err)
 				select {
-				case <-lctx.Done():
-					return
-
-				case <-ctx.Done():
-					c.logger.Debug("Context cancelled while waiting after error")
+				case <-c.ctx.Done():
 					return
 
 				case <-time.After(time.Second * 2):
@@ -59,27 +112,36 @@ func (c *Client) processEvent(ctx context.Context, id string, reader *kafka.Read
 				continue
 			}
 
-			if evs.ID == id {
-				c.logger.Debug("Received status update for task '%s': %s", id, evs.Status.String())
+			if c.options.Store != nil {
+				if err := c.options.Store.Apply(c.ctx, evs); err != nil {
+					c.logger.Warn("Failed to apply status for task '%s': %v", evs.ID, err)
+				}
+			}
 
-				select {
-				case ch <- evs:
-					// Channel send successfully
+			c.mutex.RLock()
+			subscribed := len(c.events[evs.ID]) > 0
+			c.mutex.RUnlock()
 
-				case <-lctx.Done():
-					c.logger.Debug("Kafka reader for task '%s' was cancelled", id)
-					return
+			if !subscribed {
+				continue
+			}
 
-				case <-ctx.Done():
-					c.logger.Debug("Context for task '%s' was cancelled", id)
-					return
-				}
+			c.logger.Debug("Received status update for task '%s': %s", evs.ID, evs.Status.String())
 
-				if evs.Status.IsTerminal() {
-					c.logger.Debug("Task '%s' has reached terminal status", id)
-					return
-				}
-			}
+			c.intercept(evs, c.deliver)
+		}
+	}
+}
+
+// deliver pushes the status event to all subscriptions of its task.
+func (c *Client) deliver(ev *event.StatusEvent) {
+	c.mutex.RLock()
+	subs := slices.Clone(c.events[ev.ID])
+	c.mutex.RUnlock()
+
+	for _, sub := range subs {
+		if sub.push(c, ev) {
+			c.unsubscribe(sub)
 		}
 	}
 }
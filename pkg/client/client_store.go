@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+var (
+	ErrNoStore = errors.New("no state store configured")
+)
+
+// Get returns the current state of the task with the given ID from the state store.
+func (c *Client) Get(ctx context.Context, id string) (*store.Task, error) {
+	if c.options.Store == nil {
+		return nil, ErrNoStore
+	}
+
+	return c.options.Store.Get(ctx, id)
+}
+
+// List returns the current state of all tasks matching the filter from the state store.
+func (c *Client) List(ctx context.Context, filter store.Filter) ([]*store.Task, error) {
+	if c.options.Store == nil {
+		return nil, ErrNoStore
+	}
+
+	return c.options.Store.List(ctx, filter)
+}
+
+func (c *Client) recordPending(ctx context.Context, ev *event.SubmitEvent) {
+	if c.options.Store == nil {
+		return
+	}
+
+	if err := c.options.Store.Apply(ctx, &event.StatusEvent{
+		ID:       ev.ID,
+		Time:     ev.Time,
+		Status:   event.StatusPending,
+		Metadata: ev.Metadata,
+	}); err != nil {
+		c.logger.Warn("Failed to record pending task '%s': %v", ev.ID, err)
+	}
+}
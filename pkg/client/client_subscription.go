@@ -0,0 +1,158 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+// subscription buffers the status events of a single task for its channel.
+// Events are sent without blocking the dispatcher; only if the channel is full,
+// a dedicated goroutine takes over until the queue has been drained.
+type subscription struct {
+	id    string
+	ch    chan *event.StatusEvent
+	stop  chan struct{}
+	since time.Time
+
+	// Stops the context callback once the subscription is removed
+	release func() bool
+
+	mutex    sync.Mutex
+	queue    []*event.StatusEvent
+	paused   bool
+	flushing bool
+	closed   bool
+}
+
+func newSubscription(id string, size int, paused bool) *subscription {
+	return &subscription{
+		id:     id,
+		ch:     make(chan *event.StatusEvent, size),
+		stop:   make(chan struct{}),
+		paused: paused,
+	}
+}
+
+// push queues the event and reports whether the subscription has been closed.
+func (s *subscription) push(c *Client, ev *event.StatusEvent) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed || (!s.since.IsZero() && !ev.Time.After(s.since)) {
+		return s.closed
+	}
+
+	s.queue = append(s.queue, ev)
+	return s.flush(c)
+}
+
+// resume delivers the replayed history ahead of all live events queued while paused.
+func (s *subscription) resume(c *Client, history []*event.StatusEvent) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return true
+	}
+
+	if len(history) > 0 {
+		s.since = history[len(history)-1].Time
+	}
+
+	queue := history
+	for _, ev := range s.queue {
+		if ev.Time.After(s.since) {
+			queue = append(queue, ev)
+		}
+	}
+
+	s.queue = queue
+	s.paused = false
+
+	return s.flush(c)
+}
+
+// flush sends queued events without blocking and expects the lock to be held.
+func (s *subscription) flush(c *Client) bool {
+	if s.paused || s.flushing {
+		return s.closed
+	}
+
+	for len(s.queue) > 0 {
+		ev := s.queue[0]
+
+		select {
+		case s.ch <- ev:
+			s.queue = s.queue[1:]
+			if ev.Status.IsTerminal() {
+				c.logger.Debug("Task '%s' has reached terminal status", s.id)
+				s.close()
+				return true
+			}
+
+		default:
+			s.flushing = true
+
+			c.wait.Add(1)
+			go s.drain(c)
+			return false
+		}
+	}
+
+	return false
+}
+
+// drain blocks on the channel until all queued events have been delivered.
+func (s *subscription) drain(c *Client) {
+	defer c.wait.Done()
+
+	for {
+		s.mutex.Lock()
+		if s.closed || len(s.queue) == 0 {
+			s.flushing = false
+			if s.closed {
+				close(s.ch)
+			}
+			s.mutex.Unlock()
+			return
+		}
+		ev := s.queue[0]
+		s.mutex.Unlock()
+
+		select {
+		case s.ch <- ev:
+			s.mutex.Lock()
+			s.queue = s.queue[1:]
+			terminal := ev.Status.IsTerminal()
+			if terminal {
+				c.logger.Debug("Task '%s' has reached terminal status", s.id)
+				s.close()
+			}
+			s.mutex.Unlock()
+
+			if terminal {
+				c.unsubscribe(s)
+			}
+
+		case <-s.stop:
+			// Channel is closed on the next iteration
+		}
+	}
+}
+
+// close marks the subscription as closed and expects the lock to be held.
+// While draining, the channel is closed by the draining goroutine instead.
+func (s *subscription) close() {
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	close(s.stop)
+
+	if !s.flushing {
+		close(s.ch)
+	}
+}
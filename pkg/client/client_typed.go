@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mwantia/asynk/pkg/codec"
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+)
+
+// TypedStatus is a status event with its payload decoded.
+type TypedStatus[P any] struct {
+	*event.StatusEvent
+
+	// Decoded payload of the status event; Zero if the event has no payload
+	Payload P
+	// Error returned while decoding the payload, if any
+	Err error
+}
+
+// SubmitTyped submits the task with the given payload encoded by the content type of the event.
+// Without a content type, the payload is encoded with the codec of the client.
+func SubmitTyped[T any](ctx context.Context, c *Client, ev event.SubmitEvent, payload T, opts ...options.SubmitOption) (chan *event.StatusEvent, error) {
+	if ev.ContentType == "" {
+		ev.ContentType = c.options.Codec
+	}
+
+	pc, err := c.options.Codecs.Get(ev.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := pc.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ev.Payload = data
+	return c.Submit(ctx, ev, opts...)
+}
+
+// Typed decodes the payload of every status event received on the channel by its content type.
+// The returned channel is closed once the status channel has been closed.
+func Typed[P any](c *Client, ch chan *event.StatusEvent) chan *TypedStatus[P] {
+	typed := make(chan *TypedStatus[P], cap(ch))
+
+	go func() {
+		defer close(typed)
+
+		for ev := range ch {
+			status := &TypedStatus[P]{
+				StatusEvent: ev,
+			}
+
+			if len(ev.Payload) > 0 {
+				status.Err = c.decodePayload(ev, &status.Payload)
+			}
+
+			typed <- status
+		}
+	}()
+
+	return typed
+}
+
+// decodePayload unmarshals the payload of the status event, which defaults to json without any content type.
+func (c *Client) decodePayload(ev *event.StatusEvent, v any) error {
+	contentType := ev.ContentType
+	if contentType == "" {
+		contentType = codec.ContentTypeJSON
+	}
+
+	pc, err := c.options.Codecs.Get(contentType)
+	if err != nil {
+		return err
+	}
+
+	if err := pc.Unmarshal(ev.Payload, v); err != nil {
+		return fmt.Errorf("failed to unmarshal status payload: %w", err)
+	}
+
+	return nil
+}
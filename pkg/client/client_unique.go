@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+var ErrDuplicateTask = errors.New("duplicate task")
+
+// DuplicateError is returned for rejected submits, while another task holds the unique key.
+type DuplicateError struct {
+	Key string
+	ID  string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("unique key '%s' is held by task '%s'", e.Key, e.ID)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicateTask
+}
+
+// uniqueTable holds the current holder of every unique key, as replayed in the order of the unique topic.
+type uniqueTable struct {
+	mutex   sync.Mutex
+	holders map[string]*uniqueHolder
+	waiters map[string]chan string
+	pruned  time.Time
+}
+
+type uniqueHolder struct {
+	id      string
+	expires time.Time
+}
+
+func newUniqueTable() *uniqueTable {
+	return &uniqueTable{
+		holders: make(map[string]*uniqueHolder),
+		waiters: make(map[string]chan string),
+	}
+}
+
+// claim tries to acquire the unique key for the task and returns the ID of the task holding the key.
+func (c *Client) claim(ctx context.Context, key, id string, ttl time.Duration) (string, error) {
+	c.startUnique()
+
+	now := time.Now()
+	claim := &event.UniqueEvent{
+		Key:     key,
+		ID:      id,
+		Time:    now,
+		Expires: now.Add(ttl),
+		Action:  event.UniqueClaim,
+	}
+
+	// Wait before writing to never miss the claim being resolved
+	resolved := c.uniques.wait(claim)
+	defer c.uniques.forget(claim)
+
+	writer := c.session.GetWriter("events.unique")
+
+	if err := writer.WriteEvent(ctx, claim); err != nil {
+		return "", fmt.Errorf("failed to write unique claim: %w", err)
+	}
+
+	select {
+	case holder := <-resolved:
+		return holder, nil
+
+	case <-ctx.Done():
+		return "", ctx.Err()
+
+	case <-c.ctx.Done():
+		return "", errors.New("client has been closed")
+
+	case <-time.After(time.Second * 10):
+		return "", fmt.Errorf("failed to find unique claim for task '%s'", id)
+	}
+}
+
+// startUnique starts tailing the unique topic, unless it is already running.
+// The reader is created before returning, so that no claim written afterwards is missed.
+func (c *Client) startUnique() {
+	c.unique.Do(func() {
+		reader := c.session.GetReader("events.unique", options.WithBroadcast())
+
+		c.wait.Add(1)
+		go c.processUnique(reader)
+	})
+}
+
+// processUnique replays all claims and releases and keeps tailing the unique topic.
+func (c *Client) processUnique(reader transport.Reader) {
+	defer c.wait.Done()
+
+	for {
+		ev := &event.UniqueEvent{}
+		msg, err := reader.FetchEvent(c.ctx, ev)
+		if err != nil {
+			if c.ctx.Err() != nil {
+				c.logger.Debug("Unique processing stopped")
+				return
+			}
+
+			var derr *transport.DecodeError
+			if errors.As(err, &derr) {
+				c.logger.Warn("Skipping invalid unique event for key '%s': %v", msg.Key, err)
+				continue
+			}
+
+			c.logger.Warn("Error reading unique event: %v", err)
+			select {
+			case <-c.ctx.Done():
+				return
+
+			case <-time.After(time.Second * 2):
+				// Continue after a short delay
+			}
+			continue
+		}
+
+		c.uniques.apply(msg, ev)
+	}
+}
+
+// apply resolves the claim or release by its offset. A claim is granted if the key is not held or the claim
+// of the holder has expired, measured by the time of both messages instead of the clocks of the clients.
+func (t *uniqueTable) apply(msg transport.Message, ev *event.UniqueEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if msg.Time.Sub(t.pruned) > time.Minute {
+		// Keys that are never released are dropped once their claim has expired
+		for key, holder := range t.holders {
+			if !msg.Time.Before(holder.expires) {
+				delete(t.holders, key)
+			}
+		}
+		t.pruned = msg.Time
+	}
+
+	holder := t.holders[ev.Key]
+
+	switch ev.Action {
+	case event.UniqueClaim:
+		if holder == nil || !msg.Time.Before(holder.expires) {
+			holder = &uniqueHolder{
+				id:      ev.ID,
+				expires: msg.Time.Add(ev.Expires.Sub(ev.Time)),
+			}
+			t.holders[ev.Key] = holder
+		}
+
+		if resolved, exist := t.waiters[claimKey(ev)]; exist {
+			resolved <- holder.id
+			delete(t.waiters, claimKey(ev))
+		}
+
+	case event.UniqueRelease:
+		if holder != nil && holder.id == ev.ID {
+			delete(t.holders, ev.Key)
+		}
+	}
+}
+
+// wait registers the claim and returns the channel receiving the task holding the key once it has been resolved.
+func (t *uniqueTable) wait(claim *event.UniqueEvent) chan string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	resolved := make(chan string, 1)
+	t.waiters[claimKey(claim)] = resolved
+
+	return resolved
+}
+
+func (t *uniqueTable) forget(claim *event.UniqueEvent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.waiters, claimKey(claim))
+}
+
+func claimKey(ev *event.UniqueEvent) string {
+	return fmt.Sprintf("%s|%s|%d", ev.Key, ev.ID, ev.Time.UnixNano())
+}
+
+// release gives up the unique key, if it is held by the task.
+func (c *Client) release(ctx context.Context, key, id string) {
+	writer := c.session.GetWriter("events.unique")
+
+	if err := writer.WriteEvent(ctx, &event.UniqueEvent{
+		Key:    key,
+		ID:     id,
+		Time:   time.Now(),
+		Action: event.UniqueRelease,
+	}); err != nil {
+		c.logger.Warn("Failed to release unique key '%s': %v", key, err)
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// Watch returns a status channel for an existing task. The status history still
+// retained on the status topic is replayed first, followed by all live updates
+// until the task reaches a terminal status.
+func (c *Client) Watch(ctx context.Context, id string) (chan *event.StatusEvent, error) {
+	if !c.active.Load() {
+		return nil, fmt.Errorf("client has already been closed")
+	}
+
+	if strings.TrimSpace(id) == "" {
+		return nil, errors.New("id cannot be empty")
+	}
+
+	c.logger.Info("Watching task '%s'", id)
+
+	return c.follow(ctx, id, c.history)
+}
+
+// follow subscribes to the status of the task and delivers the events returned by replay first.
+func (c *Client) follow(ctx context.Context, id string, replay func(context.Context, string) ([]*event.StatusEvent, error)) (chan *event.StatusEvent, error) {
+	// Subscribe before replaying the history to queue all live updates in the meantime
+	sub, err := c.subscribe(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := replay(ctx, id)
+	if err != nil {
+		c.close(sub)
+		return nil, err
+	}
+
+	// The replayed history is delivered through the status interceptors as well
+	intercepted := make([]*event.StatusEvent, 0, len(history))
+	for _, ev := range history {
+		c.intercept(ev, func(ev *event.StatusEvent) {
+			intercepted = append(intercepted, ev)
+		})
+	}
+	history = intercepted
+
+	if sub.resume(c, history) {
+		c.logger.Debug("Task '%s' has already reached terminal status", id)
+		c.unsubscribe(sub)
+	}
+
+	return sub.ch, nil
+}
+
+// latest returns the last known status of the task from the state store, which is enough to follow
+// a task that is still running. Otherwise, the status history of the task is read from the status topic.
+func (c *Client) latest(ctx context.Context, id string) ([]*event.StatusEvent, error) {
+	if c.options.Store != nil {
+		task, err := c.options.Store.Get(ctx, id)
+		if err == nil && !task.Status.IsTerminal() {
+			return []*event.StatusEvent{{
+				ID:       task.ID,
+				Time:     task.UpdatedAt,
+				Status:   task.Status,
+				Metadata: task.Metadata,
+			}}, nil
+		}
+	}
+
+	return c.history(ctx, id)
+}
+
+// history returns all status events of the task still retained on the status topic.
+func (c *Client) history(ctx context.Context, id string) ([]*event.StatusEvent, error) {
+	history := make([]*event.StatusEvent, 0)
+
+	// Status events are keyed by their task, so only the partition of the task is read
+	if err := c.session.ScanKey(ctx, "events.status", id, func(msg transport.Message) error {
+		evs := &event.StatusEvent{}
+		if err := c.session.Decode(msg, evs); err != nil {
+			c.logger.Warn("Skipping invalid status event for task '%s': %v", id, err)
+			return nil
+		}
+
+		history = append(history, evs)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to scan status history: %w", err)
+	}
+
+	return history, nil
+}
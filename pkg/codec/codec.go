@@ -0,0 +1,68 @@
+package codec
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeMsgpack  = "application/msgpack"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeRaw      = "application/octet-stream"
+)
+
+// IsJSON reports whether the content type describes json, which is assumed for an empty content type.
+func IsJSON(contentType string) bool {
+	return contentType == "" || contentType == ContentTypeJSON
+}
+
+// Codec encodes and decodes events and payloads for a single content type.
+type Codec interface {
+	ContentType() string
+
+	Marshal(v any) ([]byte, error)
+
+	Unmarshal(data []byte, v any) error
+}
+
+// Registry holds all codecs available to decode events and payloads by their content type.
+type Registry struct {
+	mutex  sync.RWMutex
+	codecs map[string]Codec
+}
+
+// NewRegistry creates a registry containing the json, msgpack, protobuf and raw codecs.
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{
+		codecs: make(map[string]Codec),
+	}
+
+	r.Register(JSON(), Msgpack(), Protobuf(), Raw())
+	r.Register(codecs...)
+
+	return r
+}
+
+// Register adds the codecs to the registry and replaces any codec with the same content type.
+func (r *Registry) Register(codecs ...Codec) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, c := range codecs {
+		r.codecs[c.ContentType()] = c
+	}
+}
+
+// Get returns the codec registered for the content type.
+func (r *Registry) Get(contentType string) (Codec, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	c, exist := r.codecs[contentType]
+	if !exist {
+		return nil, fmt.Errorf("no codec registered for content type '%s'", contentType)
+	}
+
+	return c, nil
+}
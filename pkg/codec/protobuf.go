@@ -0,0 +1,49 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type protobufCodec struct{}
+
+// Protobuf encodes protobuf messages and can only be used for payloads.
+func Protobuf() Codec {
+	return protobufCodec{}
+}
+
+func (protobufCodec) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("value of type '%T' is not a protobuf message", v)
+	}
+
+	return proto.Marshal(m)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		// Pointers to message pointers are allocated, as used by decoding into generic types
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Pointer {
+			return fmt.Errorf("value of type '%T' is not a protobuf message", v)
+		}
+
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+
+		if m, ok = rv.Elem().Interface().(proto.Message); !ok {
+			return fmt.Errorf("value of type '%T' is not a protobuf message", v)
+		}
+	}
+
+	return proto.Unmarshal(data, m)
+}
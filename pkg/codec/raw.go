@@ -0,0 +1,40 @@
+package codec
+
+import "fmt"
+
+type rawCodec struct{}
+
+// Raw passes bytes and strings through unchanged and can only be used for payloads.
+func Raw() Codec {
+	return rawCodec{}
+}
+
+func (rawCodec) ContentType() string {
+	return ContentTypeRaw
+}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	switch data := v.(type) {
+	case []byte:
+		return data, nil
+	case *[]byte:
+		return *data, nil
+	case string:
+		return []byte(data), nil
+	}
+
+	return nil, fmt.Errorf("value of type '%T' cannot be encoded as raw bytes", v)
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	switch target := v.(type) {
+	case *[]byte:
+		*target = append([]byte(nil), data...)
+		return nil
+	case *string:
+		*target = string(data)
+		return nil
+	}
+
+	return fmt.Errorf("value of type '%T' cannot be decoded from raw bytes", v)
+}
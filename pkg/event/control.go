@@ -0,0 +1,41 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+type ControlAction string
+
+const (
+	ActionArchive ControlAction = "archive"
+	ActionCancel  ControlAction = "cancel"
+)
+
+func (a ControlAction) String() string {
+	return string(a)
+}
+
+type ControlEvent struct {
+	ID       string        `json:"id"`
+	Time     time.Time     `json:"time,omitempty"`
+	Action   ControlAction `json:"action"`
+	Reason   string        `json:"reason,omitempty"`
+	Metadata Metadata      `json:"metadata,omitempty"`
+}
+
+func (ev *ControlEvent) GetID() string {
+	return ev.ID
+}
+
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
+func (ev *ControlEvent) Marshal() (json.RawMessage, error) {
+	return codec.JSON().Marshal(ev)
+}
+
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
+func (ev *ControlEvent) Unmarshal(data json.RawMessage) error {
+	return codec.JSON().Unmarshal(data, ev)
+}
@@ -0,0 +1,40 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+type Attempt struct {
+	Retry int       `json:"retry"`
+	Time  time.Time `json:"time"`
+	Error string    `json:"error,omitempty"`
+}
+
+type DeadEvent struct {
+	ID        string       `json:"id"`
+	Time      time.Time    `json:"time,omitempty"`
+	Payload   []byte       `json:"payload,omitempty"`
+	Submit    *SubmitEvent `json:"submit,omitempty"`
+	LastError string       `json:"last_error,omitempty"`
+	Attempts  []Attempt    `json:"attempts,omitempty"`
+	Worker    string       `json:"worker,omitempty"`
+	Host      string       `json:"host,omitempty"`
+	Metadata  Metadata     `json:"metadata,omitempty"`
+}
+
+func (ev *DeadEvent) GetID() string {
+	return ev.ID
+}
+
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
+func (ev *DeadEvent) Marshal() (json.RawMessage, error) {
+	return codec.JSON().Marshal(ev)
+}
+
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
+func (ev *DeadEvent) Unmarshal(data json.RawMessage) error {
+	return codec.JSON().Unmarshal(data, ev)
+}
@@ -8,11 +8,14 @@ import (
 	"time"
 )
 
+// Event is written to and read from the topics, encoded with the codec of the client.
 type Event interface {
 	GetID() string
 
+	// Deprecated: Events are encoded by the codec of the client instead.
 	Marshal() (json.RawMessage, error)
 
+	// Deprecated: Events are decoded by their content type instead.
 	Unmarshal(json.RawMessage) error
 }
 
@@ -0,0 +1,33 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+// HeartbeatEvent is emitted periodically by the worker processing a task.
+// Only the first heartbeat carries the submit event, the last one is marked as done once the handler has returned.
+type HeartbeatEvent struct {
+	ID      string       `json:"id"`
+	Worker  string       `json:"worker"`
+	Time    time.Time    `json:"time,omitempty"`
+	Started time.Time    `json:"started,omitempty"`
+	Done    bool         `json:"done,omitempty"`
+	Submit  *SubmitEvent `json:"submit,omitempty"`
+}
+
+func (ev *HeartbeatEvent) GetID() string {
+	return ev.ID
+}
+
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
+func (ev *HeartbeatEvent) Marshal() (json.RawMessage, error) {
+	return codec.JSON().Marshal(ev)
+}
+
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
+func (ev *HeartbeatEvent) Unmarshal(data json.RawMessage) error {
+	return codec.JSON().Unmarshal(data, ev)
+}
@@ -5,7 +5,14 @@ const (
 	MetadataRetryLimit    string = "retry_limit"
 	MetadataLastError     string = "last_error"
 	MetadataLastAttempt   string = "last_attempt"
+	MetadataNextAttempt   string = "next_attempt"
 	MetadataArchiveReason string = "archive_reason"
+	MetadataSchedule      string = "schedule"
+	MetadataScheduledAt   string = "scheduled_at"
+	MetadataUniqueKey     string = "unique_key"
+	MetadataTimeout       string = "timeout"
+	MetadataElapsed       string = "elapsed"
+	MetadataRoutingKey    string = "routing_key"
 )
 
 type Metadata map[string]string
@@ -0,0 +1,39 @@
+package event
+
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// Priorities contains all priorities, ordered from the highest to the lowest.
+var Priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+func (p Priority) String() string {
+	if p == "" {
+		return string(PriorityNormal)
+	}
+	return string(p)
+}
+
+// IsValid reports whether the priority is known; An empty priority is treated as normal.
+func (p Priority) IsValid() bool {
+	switch p {
+	case "", PriorityHigh, PriorityNormal, PriorityLow:
+		return true
+	}
+	return false
+}
+
+// Topic returns the submit topic used for tasks with this priority.
+func (p Priority) Topic() string {
+	switch p {
+	case PriorityHigh:
+		return "events.submit.high"
+	case PriorityLow:
+		return "events.submit.low"
+	}
+	return "events.submit"
+}
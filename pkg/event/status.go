@@ -3,18 +3,22 @@ package event
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
 )
 
 type Status string
 
 const (
-	StatusLost     Status = "lost"
-	StatusPending  Status = "pending"
-	StatusRunning  Status = "running"
-	StatusComplete Status = "complete"
-	StatusFailed   Status = "failed"
-	StatusRetry    Status = "retry"
-	StatusArchived Status = "archived"
+	StatusLost      Status = "lost"
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusComplete  Status = "complete"
+	StatusFailed    Status = "failed"
+	StatusRetry     Status = "retry"
+	StatusArchived  Status = "archived"
+	StatusCancelled Status = "cancelled"
+	StatusTimeout   Status = "timeout"
 )
 
 func (s Status) String() string {
@@ -22,25 +26,32 @@ func (s Status) String() string {
 }
 
 func (s Status) IsTerminal() bool {
-	return s == StatusComplete || s == StatusFailed || s == StatusArchived
+	switch s {
+	case StatusComplete, StatusFailed, StatusArchived, StatusCancelled, StatusTimeout, StatusLost:
+		return true
+	}
+	return false
 }
 
 type StatusEvent struct {
-	ID       string          `json:"id"`
-	Time     time.Time       `json:"time,omitempty"`
-	Status   Status          `json:"status"`
-	Payload  json.RawMessage `json:"payload,omitempty"`
-	Metadata Metadata        `json:"metadata,omitempty"`
+	ID          string          `json:"id"`
+	Time        time.Time       `json:"time,omitempty"`
+	Status      Status          `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Metadata    Metadata        `json:"metadata,omitempty"`
 }
 
 func (ev *StatusEvent) GetID() string {
 	return ev.ID
 }
 
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
 func (ev *StatusEvent) Marshal() (json.RawMessage, error) {
-	return json.Marshal(ev)
+	return codec.JSON().Marshal(ev)
 }
 
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
 func (ev *StatusEvent) Unmarshal(data json.RawMessage) error {
-	return json.Unmarshal(data, ev)
+	return codec.JSON().Unmarshal(data, ev)
 }
@@ -3,6 +3,8 @@ package event
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
 )
 
 type EventType string
@@ -16,21 +18,39 @@ func (s EventType) String() string {
 }
 
 type SubmitEvent struct {
-	ID       string          `json:"id"`
-	Time     time.Time       `json:"time,omitempty"`
-	Type     EventType       `json:"type,omitempty"`
-	Payload  json.RawMessage `json:"payload,omitempty"`
-	Metadata Metadata        `json:"metadata,omitempty"`
+	ID          string          `json:"id"`
+	Time        time.Time       `json:"time,omitempty"`
+	Type        EventType       `json:"type,omitempty"`
+	Priority    Priority        `json:"priority,omitempty"`
+	NotBefore   time.Time       `json:"not_before,omitempty"`
+	Deadline    time.Time       `json:"deadline,omitempty"`
+	Timeout     time.Duration   `json:"timeout,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Metadata    Metadata        `json:"metadata,omitempty"`
+	Attempts    []Attempt       `json:"attempts,omitempty"`
 }
 
 func (ev *SubmitEvent) GetID() string {
 	return ev.ID
 }
 
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
 func (ev *SubmitEvent) Marshal() (json.RawMessage, error) {
-	return json.Marshal(ev)
+	return codec.JSON().Marshal(ev)
 }
 
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
 func (ev *SubmitEvent) Unmarshal(data json.RawMessage) error {
-	return json.Unmarshal(data, ev)
+	return codec.JSON().Unmarshal(data, ev)
+}
+
+// IsDue reports whether the task may be processed at the given time.
+func (ev *SubmitEvent) IsDue(now time.Time) bool {
+	return ev.NotBefore.IsZero() || !ev.NotBefore.After(now)
+}
+
+// IsExpired reports whether the deadline of the task has passed at the given time.
+func (ev *SubmitEvent) IsExpired(now time.Time) bool {
+	return !ev.Deadline.IsZero() && now.After(ev.Deadline)
 }
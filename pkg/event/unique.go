@@ -0,0 +1,39 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+type UniqueAction string
+
+const (
+	UniqueClaim   UniqueAction = "claim"
+	UniqueRelease UniqueAction = "release"
+)
+
+// UniqueEvent claims or releases a unique key for a task. Claims are resolved in the order
+// of the unique topic, the first claim of a key is held until it is released or expires.
+type UniqueEvent struct {
+	Key     string       `json:"key"`
+	ID      string       `json:"id"`
+	Time    time.Time    `json:"time,omitempty"`
+	Expires time.Time    `json:"expires,omitempty"`
+	Action  UniqueAction `json:"action"`
+}
+
+func (ev *UniqueEvent) GetID() string {
+	return ev.Key
+}
+
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
+func (ev *UniqueEvent) Marshal() (json.RawMessage, error) {
+	return codec.JSON().Marshal(ev)
+}
+
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
+func (ev *UniqueEvent) Unmarshal(data json.RawMessage) error {
+	return codec.JSON().Unmarshal(data, ev)
+}
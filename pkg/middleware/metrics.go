@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/server"
+)
+
+// Recorder receives metrics about every processed task.
+type Recorder interface {
+	Started(p *server.Pipeline)
+
+	Finished(p *server.Pipeline, elapsed time.Duration, err error)
+}
+
+// Metrics reports the start and the result of every task to the recorder.
+func Metrics(recorder Recorder) server.Middleware {
+	return func(next server.Handler) server.Handler {
+		return server.HandlerFunc(func(ctx context.Context, p *server.Pipeline) error {
+			started := time.Now()
+			recorder.Started(p)
+
+			defer func() {
+				// Panics are recorded as failures, before they are passed on
+				if r := recover(); r != nil {
+					recorder.Finished(p, time.Since(started), fmt.Errorf("%w: %v", server.ErrPanic, r))
+					panic(r)
+				}
+			}()
+
+			err := next.ProcessPipeline(ctx, p)
+			recorder.Finished(p, time.Since(started), err)
+
+			return err
+		})
+	}
+}
+
+var _ Recorder = (*Counters)(nil)
+
+// Counters is a simple recorder counting all processed tasks.
+type Counters struct {
+	inflight  atomic.Int64
+	succeeded atomic.Int64
+	failed    atomic.Int64
+	duration  atomic.Int64
+}
+
+type CountersSnapshot struct {
+	InFlight  int64         `json:"inflight"`
+	Succeeded int64         `json:"succeeded"`
+	Failed    int64         `json:"failed"`
+	Duration  time.Duration `json:"duration"`
+}
+
+func NewCounters() *Counters {
+	return &Counters{}
+}
+
+func (c *Counters) Started(p *server.Pipeline) {
+	c.inflight.Add(1)
+}
+
+func (c *Counters) Finished(p *server.Pipeline, elapsed time.Duration, err error) {
+	c.inflight.Add(-1)
+	c.duration.Add(int64(elapsed))
+
+	if err != nil {
+		c.failed.Add(1)
+	} else {
+		c.succeeded.Add(1)
+	}
+}
+
+// Snapshot returns the current values of all counters.
+func (c *Counters) Snapshot() CountersSnapshot {
+	return CountersSnapshot{
+		InFlight:  c.inflight.Load(),
+		Succeeded: c.succeeded.Load(),
+		Failed:    c.failed.Load(),
+		Duration:  time.Duration(c.duration.Load()),
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/server"
+)
+
+// Logging logs the start and the result of every task.
+func Logging() server.Middleware {
+	return func(next server.Handler) server.Handler {
+		return server.HandlerFunc(func(ctx context.Context, p *server.Pipeline) error {
+			started := time.Now()
+			p.Logger().Info("Processing task '%s' of type '%s'", p.Submit().ID, p.Submit().Type)
+
+			err := next.ProcessPipeline(ctx, p)
+			if err != nil {
+				p.Logger().Warn("Task '%s' failed after '%v': %v", p.Submit().ID, time.Since(started), err)
+				return err
+			}
+
+			p.Logger().Info("Task '%s' finished after '%v'", p.Submit().ID, time.Since(started))
+			return nil
+		})
+	}
+}
+
+// Timeout cancels the handler context after the given duration with server.ErrTimeout as cause.
+// Tasks failing due to the timeout are reported with a timeout status.
+func Timeout(timeout time.Duration) server.Middleware {
+	return func(next server.Handler) server.Handler {
+		return server.HandlerFunc(func(ctx context.Context, p *server.Pipeline) error {
+			started := time.Now()
+
+			process, cancel := context.WithTimeoutCause(ctx, timeout, server.ErrTimeout)
+			defer cancel()
+
+			err := next.ProcessPipeline(process, p)
+			if err != nil && ctx.Err() == nil && errors.Is(context.Cause(process), server.ErrTimeout) {
+				return &server.TimeoutError{
+					Timeout: timeout,
+					Elapsed: time.Since(started),
+					Err:     server.ErrTimeout,
+				}
+			}
+
+			return err
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package options
+
+// BrokerOptions configure the in-memory broker.
+type BrokerOptions struct {
+	NumPartitions int `json:"num_partitions,omitempty"`
+}
+
+type BrokerOption func(*BrokerOptions)
+
+// WithDefaultPartitions defines the number of partitions for topics created without an explicit partition count.
+func WithDefaultPartitions(numPartitions int) BrokerOption {
+	return func(o *BrokerOptions) {
+		o.NumPartitions = numPartitions
+	}
+}
@@ -1,18 +1,23 @@
 package options
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/mwantia/asynk/pkg/codec"
 	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/store"
 )
 
 const (
 	DefaultBroker      = "localhost:9092"
 	DefaultNetwork     = "tcp"
 	DefaultGroupID     = ""
+	DefaultStatusGroup = ""
 	DefaultTopicPrefix = "asynk"
 	DefaultPool        = "default"
 	DefaultLogLevel    = "INFO"
+	DefaultCodec       = codec.ContentTypeJSON
 
 	DefaultMaxWait         = time.Millisecond * 50
 	DefaultCommitInterval  = time.Millisecond * 100
@@ -27,23 +32,27 @@ const (
 )
 
 type ClientOptions struct {
-	Logger          *log.LogBase  `json:"-"`
-	Brokers         []string      `json:"brokers,omitempty"`
-	Network         string        `json:"network,omitempty"`
-	GroupID         string        `json:"group_id,omitempty"`
-	TopicPrefix     string        `json:"topic_prefix,omitempty"`
-	Pool            string        `json:"pool,omitempty"`
-	LogLevel        string        `json:"log_level,omitempty"`
-	MaxWait         time.Duration `json:"max_wait,omitempty"`
-	CommitInterval  time.Duration `json:"commit_interval,omitempty"`
-	MinBytes        int64         `json:"min_bytes,omitempty"`
-	MaxBytes        int64         `json:"max_bytes,omitempty"`
-	ConnectTimeout  time.Duration `json:"connect_timeout,omitempty"`
-	ShutdownTimeout time.Duration `json:"shutdown_timeout,omitempty"`
-	BatchSize       int           `json:"batch_size,omitempty"`
-	BatchBytes      int64         `json:"batch_bytes,omitempty"`
-	BatchTimeout    time.Duration `json:"batch_timeout,omitempty"`
-	Async           bool          `json:"async,omitempty"`
+	Logger          *log.LogBase    `json:"-"`
+	Store           store.Store     `json:"-"`
+	Codecs          *codec.Registry `json:"-"`
+	Codec           string          `json:"codec,omitempty"`
+	Brokers         []string        `json:"brokers,omitempty"`
+	Network         string          `json:"network,omitempty"`
+	GroupID         string          `json:"group_id,omitempty"`
+	StatusGroupID   string          `json:"status_group_id,omitempty"`
+	TopicPrefix     string          `json:"topic_prefix,omitempty"`
+	Pool            string          `json:"pool,omitempty"`
+	LogLevel        string          `json:"log_level,omitempty"`
+	MaxWait         time.Duration   `json:"max_wait,omitempty"`
+	CommitInterval  time.Duration   `json:"commit_interval,omitempty"`
+	MinBytes        int64           `json:"min_bytes,omitempty"`
+	MaxBytes        int64           `json:"max_bytes,omitempty"`
+	ConnectTimeout  time.Duration   `json:"connect_timeout,omitempty"`
+	ShutdownTimeout time.Duration   `json:"shutdown_timeout,omitempty"`
+	BatchSize       int             `json:"batch_size,omitempty"`
+	BatchBytes      int64           `json:"batch_bytes,omitempty"`
+	BatchTimeout    time.Duration   `json:"batch_timeout,omitempty"`
+	Async           bool            `json:"async,omitempty"`
 }
 
 func DefaultClientOptions() ClientOptions {
@@ -53,9 +62,12 @@ func DefaultClientOptions() ClientOptions {
 		},
 		Network:         DefaultNetwork,
 		GroupID:         DefaultGroupID,
+		StatusGroupID:   DefaultStatusGroup,
 		TopicPrefix:     DefaultTopicPrefix,
 		Pool:            DefaultPool,
 		LogLevel:        DefaultLogLevel,
+		Codecs:          codec.NewRegistry(),
+		Codec:           DefaultCodec,
 		MaxWait:         DefaultMaxWait,
 		CommitInterval:  DefaultCommitInterval,
 		MinBytes:        DefaultMinBytes,
@@ -78,6 +90,13 @@ func WithLogger(logger *log.LogBase) ClientOption {
 	}
 }
 
+func WithStore(store store.Store) ClientOption {
+	return func(o *ClientOptions) error {
+		o.Store = store
+		return nil
+	}
+}
+
 func WithBrokers(brokers ...string) ClientOption {
 	return func(o *ClientOptions) error {
 		o.Brokers = brokers
@@ -99,6 +118,16 @@ func WithGroupID(groupID string) ClientOption {
 	}
 }
 
+// WithStatusGroupID defines a stable consumer group used by a client to read status events, so that a restarted
+// client resumes from its last committed offset. The group must be unique for every client, otherwise status updates
+// will be split between them. Without a group, a client only reads status events written after it has started.
+func WithStatusGroupID(groupID string) ClientOption {
+	return func(o *ClientOptions) error {
+		o.StatusGroupID = groupID
+		return nil
+	}
+}
+
 func WithTopicPrefix(topicPrefix string) ClientOption {
 	return func(o *ClientOptions) error {
 		o.TopicPrefix = topicPrefix
@@ -201,3 +230,27 @@ func WithAsync(async bool) ClientOption {
 		return nil
 	}
 }
+
+// WithCodec defines the content type used to encode all written events and typed payloads.
+// Events are always decoded by the content type they have been written with. The codec has to support
+// events, like json or msgpack; Payload-only codecs like protobuf or raw bytes are rejected by clients and servers.
+func WithCodec(contentType string) ClientOption {
+	return func(o *ClientOptions) error {
+		o.Codec = contentType
+		return nil
+	}
+}
+
+// WithCodecs registers additional codecs, replacing any codec with the same content type.
+func WithCodecs(codecs ...codec.Codec) ClientOption {
+	return func(o *ClientOptions) error {
+		for _, c := range codecs {
+			if c == nil {
+				return fmt.Errorf("codec cannot be nil")
+			}
+		}
+
+		o.Codecs.Register(codecs...)
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package options
+
+type ReaderOptions struct {
+	GroupID   string `json:"group_id,omitempty"`
+	Broadcast bool   `json:"broadcast,omitempty"`
+	Latest    bool   `json:"latest,omitempty"`
+}
+
+type ReaderOption func(*ReaderOptions)
+
+// WithReaderGroupID overrides the consumer group defined by the client options.
+func WithReaderGroupID(groupID string) ReaderOption {
+	return func(o *ReaderOptions) {
+		o.GroupID = groupID
+	}
+}
+
+// WithBroadcast creates a reader that receives every message of the topic,
+// independent of any consumer group.
+func WithBroadcast() ReaderOption {
+	return func(o *ReaderOptions) {
+		o.Broadcast = true
+	}
+}
+
+// WithLatest starts a broadcast reader at the end of every partition, so that only messages
+// written after the reader has been created are received. Topics that do not exist yet are read from the start.
+func WithLatest() ReaderOption {
+	return func(o *ReaderOptions) {
+		o.Latest = true
+	}
+}
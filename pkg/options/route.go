@@ -0,0 +1,264 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+const (
+	DefaultConcurrency    = 1
+	DefaultDelivery       = DeliveryAtLeastOnce
+	DefaultMaxRetries     = 0
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute * 5
+	DefaultMultiplier     = 2.0
+	DefaultJitter         = 0.2
+	DefaultPriorityMode   = PriorityWeighted
+	DefaultProcessedTTL   = time.Hour * 24
+	DefaultTimeout        = time.Minute * 5
+	DefaultHeartbeat      = time.Second * 10
+	DefaultLostAfter      = time.Second * 30
+)
+
+var DefaultPriorityWeights = PriorityWeights{
+	High:   6,
+	Normal: 3,
+	Low:    1,
+}
+
+type DeliveryMode string
+
+const (
+	// Offsets are committed once the handler has finished; Tasks are redelivered after a crash
+	DeliveryAtLeastOnce DeliveryMode = "at-least-once"
+	// Offsets are committed before the handler is called; Tasks are lost after a crash
+	DeliveryAtMostOnce DeliveryMode = "at-most-once"
+)
+
+type PriorityMode string
+
+const (
+	// Tasks of all priorities are picked in proportion to their weights; Low priorities are never starved
+	PriorityWeighted PriorityMode = "weighted"
+	// Tasks of a lower priority are only picked if no task of a higher priority is available
+	PriorityStrict PriorityMode = "strict"
+)
+
+type RouteOptions struct {
+	Concurrency int             `json:"concurrency,omitempty"`
+	Delivery    DeliveryMode    `json:"delivery,omitempty"`
+	Timeout     time.Duration   `json:"timeout,omitempty"`
+	Heartbeat   time.Duration   `json:"heartbeat,omitempty"`
+	LostAfter   time.Duration   `json:"lost_after,omitempty"`
+	RetryLost   bool            `json:"retry_lost,omitempty"`
+	Retry       RetryPolicy     `json:"retry,omitempty"`
+	Priority    PriorityMode    `json:"priority,omitempty"`
+	Weights     PriorityWeights `json:"weights,omitempty"`
+
+	Processed    store.Processed `json:"-"`
+	ProcessedTTL time.Duration   `json:"processed_ttl,omitempty"`
+}
+
+type PriorityWeights struct {
+	High   int `json:"high,omitempty"`
+	Normal int `json:"normal,omitempty"`
+	Low    int `json:"low,omitempty"`
+}
+
+type RetryPolicy struct {
+	MaxRetries     int           `json:"max_retries,omitempty"`
+	InitialBackoff time.Duration `json:"initial_backoff,omitempty"`
+	MaxBackoff     time.Duration `json:"max_backoff,omitempty"`
+	Multiplier     float64       `json:"multiplier,omitempty"`
+	Jitter         float64       `json:"jitter,omitempty"`
+	NonRetryable   []error       `json:"-"`
+}
+
+func DefaultRouteOptions() RouteOptions {
+	return RouteOptions{
+		Concurrency: DefaultConcurrency,
+		Delivery:    DefaultDelivery,
+		Timeout:     DefaultTimeout,
+		Heartbeat:   DefaultHeartbeat,
+		LostAfter:   DefaultLostAfter,
+		Retry: RetryPolicy{
+			MaxRetries:     DefaultMaxRetries,
+			InitialBackoff: DefaultInitialBackoff,
+			MaxBackoff:     DefaultMaxBackoff,
+			Multiplier:     DefaultMultiplier,
+			Jitter:         DefaultJitter,
+		},
+		Priority:     DefaultPriorityMode,
+		Weights:      DefaultPriorityWeights,
+		ProcessedTTL: DefaultProcessedTTL,
+	}
+}
+
+// Backoff returns the delay before the given retry, starting with retry '1'.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// IsRetryable reports whether the error is not matched by any of the non-retryable errors.
+func (p RetryPolicy) IsRetryable(err error) bool {
+	for _, target := range p.NonRetryable {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type RouteOption func(*RouteOptions) error
+
+// WithConcurrency defines the maximum number of pipelines processed in parallel.
+func WithConcurrency(concurrency int) RouteOption {
+	return func(o *RouteOptions) error {
+		if concurrency < 1 {
+			return errors.New("concurrency must be at least 1")
+		}
+		o.Concurrency = concurrency
+		return nil
+	}
+}
+
+// WithTimeout defines how long a task may be processed, unless the task defines its own timeout.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(o *RouteOptions) error {
+		if timeout <= 0 {
+			return errors.New("timeout must be positive")
+		}
+		o.Timeout = timeout
+		return nil
+	}
+}
+
+// WithHeartbeat defines how often running tasks emit heartbeats and after which
+// duration without any heartbeat a task is considered lost.
+func WithHeartbeat(interval, lostAfter time.Duration) RouteOption {
+	return func(o *RouteOptions) error {
+		if interval <= 0 || lostAfter <= interval {
+			return errors.New("lost duration must be greater than the heartbeat interval")
+		}
+		o.Heartbeat = interval
+		o.LostAfter = lostAfter
+		return nil
+	}
+}
+
+// WithRetryLost retries lost tasks according to the retry policy, instead of reporting them as lost.
+func WithRetryLost() RouteOption {
+	return func(o *RouteOptions) error {
+		o.RetryLost = true
+		return nil
+	}
+}
+
+func WithDeliveryMode(mode DeliveryMode) RouteOption {
+	return func(o *RouteOptions) error {
+		switch mode {
+		case DeliveryAtLeastOnce, DeliveryAtMostOnce:
+			o.Delivery = mode
+			return nil
+		}
+		return fmt.Errorf("invalid delivery mode '%s'", mode)
+	}
+}
+
+func WithMaxRetries(retries int) RouteOption {
+	return func(o *RouteOptions) error {
+		if retries < 0 {
+			return errors.New("max retries cannot be negative")
+		}
+		o.Retry.MaxRetries = retries
+		return nil
+	}
+}
+
+func WithBackoff(initial, max time.Duration, multiplier float64) RouteOption {
+	return func(o *RouteOptions) error {
+		if initial <= 0 || multiplier < 1 {
+			return errors.New("invalid backoff configuration")
+		}
+		o.Retry.InitialBackoff = initial
+		o.Retry.MaxBackoff = max
+		o.Retry.Multiplier = multiplier
+		return nil
+	}
+}
+
+func WithJitter(jitter float64) RouteOption {
+	return func(o *RouteOptions) error {
+		if jitter < 0 || jitter > 1 {
+			return errors.New("jitter must be between 0 and 1")
+		}
+		o.Retry.Jitter = jitter
+		return nil
+	}
+}
+
+func WithNonRetryable(errs ...error) RouteOption {
+	return func(o *RouteOptions) error {
+		o.Retry.NonRetryable = append(o.Retry.NonRetryable, errs...)
+		return nil
+	}
+}
+
+// WithStrictPriority always prefers tasks of a higher priority.
+func WithStrictPriority() RouteOption {
+	return func(o *RouteOptions) error {
+		o.Priority = PriorityStrict
+		return nil
+	}
+}
+
+// WithPriorityWeights picks tasks of each priority in proportion to the given weights.
+func WithPriorityWeights(high, normal, low int) RouteOption {
+	return func(o *RouteOptions) error {
+		if high < 1 || normal < 1 || low < 1 {
+			return errors.New("priority weights must be at least 1")
+		}
+		o.Priority = PriorityWeighted
+		o.Weights = PriorityWeights{
+			High:   high,
+			Normal: normal,
+			Low:    low,
+		}
+		return nil
+	}
+}
+
+// WithProcessedStore remembers finished tasks for the given ttl, so that tasks delivered
+// again are skipped and only their terminal status is emitted again.
+func WithProcessedStore(processed store.Processed, ttl time.Duration) RouteOption {
+	return func(o *RouteOptions) error {
+		if processed == nil {
+			return errors.New("processed store cannot be nil")
+		}
+		if ttl <= 0 {
+			return errors.New("processed ttl must be positive")
+		}
+		o.Processed = processed
+		o.ProcessedTTL = ttl
+		return nil
+	}
+}
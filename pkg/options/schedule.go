@@ -0,0 +1,72 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type MissedPolicy string
+
+const (
+	// Missed runs are skipped and only the next regular run is fired
+	MissedSkip MissedPolicy = "skip"
+	// All missed runs are combined into a single run
+	MissedCatchUpOnce MissedPolicy = "catch-up-once"
+	// Every missed run is fired, limited by the maximum catch up
+	MissedCatchUpAll MissedPolicy = "catch-up-all"
+)
+
+const (
+	DefaultMissedPolicy    = MissedSkip
+	DefaultMissedThreshold = time.Minute
+	DefaultMaxCatchUp      = 100
+)
+
+type ScheduleOptions struct {
+	Missed          MissedPolicy  `json:"missed,omitempty"`
+	MissedThreshold time.Duration `json:"missed_threshold,omitempty"`
+	MaxCatchUp      int           `json:"max_catch_up,omitempty"`
+}
+
+func DefaultScheduleOptions() ScheduleOptions {
+	return ScheduleOptions{
+		Missed:          DefaultMissedPolicy,
+		MissedThreshold: DefaultMissedThreshold,
+		MaxCatchUp:      DefaultMaxCatchUp,
+	}
+}
+
+type ScheduleOption func(*ScheduleOptions) error
+
+func WithMissedPolicy(policy MissedPolicy) ScheduleOption {
+	return func(o *ScheduleOptions) error {
+		switch policy {
+		case MissedSkip, MissedCatchUpOnce, MissedCatchUpAll:
+			o.Missed = policy
+			return nil
+		}
+		return fmt.Errorf("invalid missed policy '%s'", policy)
+	}
+}
+
+// WithMissedThreshold defines how late a run may be fired before it is considered as missed.
+func WithMissedThreshold(threshold time.Duration) ScheduleOption {
+	return func(o *ScheduleOptions) error {
+		if threshold <= 0 {
+			return errors.New("missed threshold must be positive")
+		}
+		o.MissedThreshold = threshold
+		return nil
+	}
+}
+
+func WithMaxCatchUp(max int) ScheduleOption {
+	return func(o *ScheduleOptions) error {
+		if max < 1 {
+			return errors.New("max catch up must be at least 1")
+		}
+		o.MaxCatchUp = max
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type UniqueMode string
+
+const (
+	// A duplicate submit returns the status channel of the task already holding the key
+	UniqueCollapse UniqueMode = "collapse"
+	// A duplicate submit is rejected with an error
+	UniqueReject UniqueMode = "reject"
+)
+
+const (
+	DefaultUniqueMode = UniqueCollapse
+	// Unique keys can never be held longer than they are retained on the unique topic
+	MaxUniqueTTL = time.Hour * 24 * 7
+)
+
+type SubmitOptions struct {
+	UniqueKey  string        `json:"unique_key,omitempty"`
+	UniqueTTL  time.Duration `json:"unique_ttl,omitempty"`
+	UniqueMode UniqueMode    `json:"unique_mode,omitempty"`
+}
+
+func DefaultSubmitOptions() SubmitOptions {
+	return SubmitOptions{
+		UniqueMode: DefaultUniqueMode,
+	}
+}
+
+type SubmitOption func(*SubmitOptions) error
+
+// WithUniqueKey only submits the task, if no other task with the same key is pending or running.
+// The key is released once the task reaches a terminal status or the ttl has passed.
+func WithUniqueKey(key string, ttl time.Duration) SubmitOption {
+	return func(o *SubmitOptions) error {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("unique key cannot be empty")
+		}
+		if ttl <= 0 || ttl > MaxUniqueTTL {
+			return fmt.Errorf("unique ttl must be between 0 and '%v'", MaxUniqueTTL)
+		}
+		o.UniqueKey = key
+		o.UniqueTTL = ttl
+		return nil
+	}
+}
+
+func WithUniqueMode(mode UniqueMode) SubmitOption {
+	return func(o *SubmitOptions) error {
+		switch mode {
+		case UniqueCollapse, UniqueReject:
+			o.UniqueMode = mode
+			return nil
+		}
+		return fmt.Errorf("invalid unique mode '%s'", mode)
+	}
+}
@@ -5,7 +5,7 @@ import "time"
 const (
 	DefaultNumPartitions     = -1
 	DefaultReplicationFactor = -1
-	DefaultRetentionTime     = 86400000
+	DefaultRetentionTime     = time.Hour * 24
 	DefaultRetentionBytes    = 173741824
 )
 
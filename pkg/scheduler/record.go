@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+type recordKind string
+
+const (
+	recordHeartbeat recordKind = "heartbeat"
+	recordTick      recordKind = "tick"
+	recordClaim     recordKind = "claim"
+)
+
+// record is shared between all scheduler replicas on the leader topic.
+// Heartbeats are used for the leader election, claims to fence off previous leaders
+// and ticks to remember the last fired run of every schedule.
+type record struct {
+	Node     string     `json:"node"`
+	Time     time.Time  `json:"time"`
+	Kind     recordKind `json:"kind"`
+	Schedule string     `json:"schedule,omitempty"`
+	Tick     time.Time  `json:"tick,omitempty"`
+}
+
+func (r *record) GetID() string {
+	return r.Node
+}
+
+// Deprecated: Events are encoded by the codec of the client; Marshal always encodes the event as json.
+func (r *record) Marshal() (json.RawMessage, error) {
+	return codec.JSON().Marshal(r)
+}
+
+// Deprecated: Events are decoded by their content type; Unmarshal always decodes the event as json.
+func (r *record) Unmarshal(data json.RawMessage) error {
+	return codec.JSON().Unmarshal(data, r)
+}
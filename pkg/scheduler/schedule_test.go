@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every 10ms",
+		"@every soon",
+	} {
+		if _, err := Cron(expr); err == nil {
+			t.Fatalf("expected expression '%s' to be rejected", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2026-01-01 is a thursday
+	from := time.Date(2026, 1, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{expr: "*/15 * * * *", expected: time.Date(2026, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{expr: "0 2 * * *", expected: time.Date(2026, 1, 2, 2, 0, 0, 0, time.UTC)},
+		{expr: "30 9-17/4 * * *", expected: time.Date(2026, 1, 1, 13, 30, 0, 0, time.UTC)},
+		{expr: "@weekly", expected: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", expected: time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", expected: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields are restricted, so either the 10th or a monday matches
+		{expr: "0 0 10 * 1", expected: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// A stepped wildcard does not restrict the day, so only odd mondays match
+		{expr: "0 0 */2 * 1", expected: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 */2 * 6", expected: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		// A stepped range restricts the day, so either an even day or a saturday matches
+		{expr: "0 0 2-31/2 * 6", expected: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		schedule, err := Cron(test.expr)
+		if err != nil {
+			t.Fatalf("failed to parse '%s': %v", test.expr, err)
+		}
+
+		if next := schedule.Next(from); !next.Equal(test.expected) {
+			t.Fatalf("expected '%s' to fire at '%v', got '%v'", test.expr, test.expected, next)
+		}
+	}
+}
+
+func TestCronNeverFires(t *testing.T) {
+	schedule, err := Cron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("failed to parse expression: %v", err)
+	}
+
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected schedule to never fire, got '%v'", next)
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	schedule, err := Every(time.Minute * 10)
+	if err != nil {
+		t.Fatalf("failed to create schedule: %v", err)
+	}
+
+	from := time.Date(2026, 1, 1, 10, 7, 30, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(time.Date(2026, 1, 1, 10, 10, 0, 0, time.UTC)) {
+		t.Fatalf("expected schedule to fire at 10:10, got '%v'", next)
+	}
+
+	if _, err := Every(time.Millisecond); err == nil {
+		t.Fatal("expected interval below one second to be rejected")
+	}
+}
+
+func TestCatchUpFrom(t *testing.T) {
+	schedule, _ := Every(time.Minute * 10)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Only the last runs fitting into the catch-up window are considered
+	last := now.Add(-time.Hour * 24)
+	if from := catchUpFrom(schedule, last, now, 2); !from.Equal(now.Add(-time.Minute * 30)) {
+		t.Fatalf("expected catch-up to start 30 minutes ago, got '%v'", from)
+	}
+
+	last = now.Add(-time.Minute * 15)
+	if from := catchUpFrom(schedule, last, now, 2); !from.Equal(last) {
+		t.Fatalf("expected catch-up to start at the last run, got '%v'", from)
+	}
+
+	never, _ := Cron("0 0 31 2 *")
+	if from := catchUpFrom(never, last, now, 2); !from.Equal(last) {
+		t.Fatalf("expected catch-up to start at the last run, got '%v'", from)
+	}
+}
@@ -0,0 +1,442 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mwantia/asynk/internal/kafka"
+	basic "github.com/mwantia/asynk/internal/log"
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+const (
+	leaseInterval = time.Second * 5
+	leaseTimeout  = time.Second * 15
+)
+
+// Scheduler submits tasks for registered schedules. When running several replicas, only the elected leader
+// fires the schedules. Runs are submitted at least once: A leader failing after submitting a run but before
+// recording it leaves the run to be submitted again by its successor, with the same task ID.
+type Scheduler struct {
+	logger  log.LogWrapper
+	mutex   sync.RWMutex
+	client  transport.Transport
+	entries map[string]*scheduleEntry
+	peers   map[string]time.Time
+	claim   record
+	active  atomic.Bool
+}
+
+type scheduleEntry struct {
+	name     string
+	suffix   string
+	schedule Schedule
+	template event.SubmitEvent
+	options  options.ScheduleOptions
+	last     time.Time
+}
+
+func NewScheduler(opts ...options.ClientOption) (*Scheduler, error) {
+	return NewSchedulerWithTransport(nil, opts...)
+}
+
+// NewSchedulerWithTransport creates a scheduler using the provided transport.
+// If no transport is provided, a kafka transport is created from the options.
+func NewSchedulerWithTransport(t transport.Transport, opts ...options.ClientOption) (*Scheduler, error) {
+	options := options.DefaultClientOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := transport.ValidateCodec(options.Codecs, options.Codec); err != nil {
+		return nil, fmt.Errorf("invalid codec: %w", err)
+	}
+
+	var logger log.LogWrapper
+
+	if options.Logger != nil {
+		logger = basic.NewNamed(*options.Logger, "asynk/scheduler")
+	}
+	if logger == nil {
+		l := basic.NewBasic(options.LogLevel)
+		logger = l.Named("asynk/scheduler")
+	}
+
+	if t == nil {
+		k, err := kafka.NewKafka(options, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka client: %w", err)
+		}
+		t = k
+	}
+
+	return &Scheduler{
+		logger:  logger,
+		client:  t,
+		entries: make(map[string]*scheduleEntry),
+		peers:   make(map[string]time.Time),
+	}, nil
+}
+
+// Register adds a schedule that submits a copy of the template to the suffix on every run.
+func (s *Scheduler) Register(name, suffix string, schedule Schedule, template event.SubmitEvent, opts ...options.ScheduleOption) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid name")
+	}
+	if strings.TrimSpace(suffix) == "" {
+		return fmt.Errorf("invalid suffix")
+	}
+	if schedule == nil {
+		return fmt.Errorf("invalid schedule")
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("invalid schedule: schedule never fires")
+	}
+
+	if _, exist := s.entries[name]; exist {
+		return fmt.Errorf("schedule already registered")
+	}
+
+	options := options.DefaultScheduleOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return fmt.Errorf("failed to apply schedule option: %w", err)
+		}
+	}
+
+	s.entries[name] = &scheduleEntry{
+		name:     name,
+		suffix:   suffix,
+		schedule: schedule,
+		template: template,
+		options:  options,
+	}
+
+	return nil
+}
+
+// Run participates in the leader election and fires all due schedules while being the leader.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if !s.active.CompareAndSwap(false, true) {
+		return fmt.Errorf("scheduler is already running")
+	}
+	defer s.active.Store(false)
+
+	session, err := s.client.Session("scheduler")
+	if err != nil {
+		return fmt.Errorf("failed to create scheduler session: %w", err)
+	}
+
+	if err := session.CreateTopic(ctx, "events.leader",
+		options.WithNumPartitions(1),
+		options.WithRetentionTime(time.Hour*24*7),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.leader", err)
+	}
+
+	node := session.GetID()
+	started := time.Now()
+
+	s.mutex.RLock()
+	s.logger.Info("Starting scheduler '%s' with '%d' schedules", node, len(s.entries))
+	s.mutex.RUnlock()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.processRecords(ctx, session)
+	}()
+
+	writer := session.GetWriter("events.leader")
+	sessions := make(map[string]transport.Session)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var heartbeat, claimed time.Time
+	leading := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Scheduler stopped, waiting for goroutines to complete...")
+			wg.Wait()
+
+			if err := s.client.Cleanup(); err != nil {
+				return fmt.Errorf("failed to perform client cleanup: %w", err)
+			}
+			return nil
+
+		case now := <-ticker.C:
+			// A lapsed lease has to be claimed again, as another replica may have taken over meanwhile
+			lapsed := now.Sub(heartbeat) > leaseTimeout
+
+			if now.Sub(heartbeat) >= leaseInterval {
+				if err := writer.WriteEvent(ctx, &record{
+					Node: node,
+					Time: now,
+					Kind: recordHeartbeat,
+				}); err != nil {
+					s.logger.Warn("Failed to write heartbeat: %v", err)
+				} else {
+					heartbeat = now
+				}
+			}
+
+			// Wait until the heartbeats of all other replicas have been observed
+			leader := !lapsed && s.leader(node, heartbeat, now) && now.Sub(started) >= leaseTimeout
+			if leader && !leading {
+				if err := writer.WriteEvent(ctx, &record{
+					Node: node,
+					Time: now,
+					Kind: recordClaim,
+				}); err != nil {
+					s.logger.Warn("Failed to write claim: %v", err)
+					continue
+				}
+				claimed = now
+			}
+			if leader != leading {
+				s.logger.Info("Scheduler '%s' leadership changed to '%v'", node, leader)
+				leading = leader
+			}
+
+			if !leading {
+				continue
+			}
+
+			// Fire only once the own claim has been read back, so that all ticks fired by a previous leader
+			// have been observed, and stop as soon as a claim of another replica follows
+			if !s.holdsClaim(node, claimed) {
+				s.logger.Debug("Waiting for claim of scheduler '%s' to be observed", node)
+				continue
+			}
+
+			s.mutex.RLock()
+			entries := make([]*scheduleEntry, 0, len(s.entries))
+			for _, entry := range s.entries {
+				entries = append(entries, entry)
+			}
+			s.mutex.RUnlock()
+
+			for _, entry := range entries {
+				if err := s.fire(ctx, node, started, entry, now, writer, sessions); err != nil {
+					s.logger.Error("Failed to fire schedule '%s': %v", entry.name, err)
+				}
+			}
+		}
+	}
+}
+
+// holdsClaim reports whether the claim is the latest one observed on the leader topic.
+func (s *Scheduler) holdsClaim(node string, claimed time.Time) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.claim.Node == node && s.claim.Time.Equal(claimed)
+}
+
+// leader reports whether the node is the oldest replica with a valid lease.
+func (s *Scheduler) leader(node string, heartbeat, now time.Time) bool {
+	if now.Sub(heartbeat) > leaseTimeout {
+		return false
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// UUIDv7 identifiers are ordered by their creation time
+	for peer, seen := range s.peers {
+		if peer < node && now.Sub(seen) <= leaseTimeout {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Scheduler) processRecords(ctx context.Context, session transport.Session) {
+	reader := session.GetReader("events.leader", options.WithBroadcast())
+
+	for {
+		rec := &record{}
+		if err := reader.ReadEvent(ctx, rec); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			var derr *transport.DecodeError
+			if errors.As(err, &derr) {
+				continue
+			}
+
+			s.logger.Warn("Error reading leader record: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Second * 2):
+				// Continue after a short delay
+			}
+			continue
+		}
+
+		s.mutex.Lock()
+		switch rec.Kind {
+		case recordHeartbeat:
+			if rec.Time.After(s.peers[rec.Node]) {
+				s.peers[rec.Node] = rec.Time
+			}
+
+		case recordClaim:
+			s.claim = *rec
+
+		case recordTick:
+			if entry, exist := s.entries[rec.Schedule]; exist && rec.Tick.After(entry.last) {
+				entry.last = rec.Tick
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// fire submits all due runs of the schedule according to its missed-run policy.
+func (s *Scheduler) fire(ctx context.Context, node string, started time.Time, entry *scheduleEntry, now time.Time, writer transport.Writer, sessions map[string]transport.Session) error {
+	s.mutex.RLock()
+	last := entry.last
+	s.mutex.RUnlock()
+
+	if last.IsZero() {
+		// Schedules without any previous run only consider runs since the startup
+		last = started
+	}
+
+	// Runs before the catch-up window are never submitted, so they are not iterated either
+	last = catchUpFrom(entry.schedule, last, now, entry.options.MaxCatchUp)
+
+	var ticks []time.Time
+	for next := entry.schedule.Next(last); !next.IsZero() && !next.After(now); next = entry.schedule.Next(next) {
+		ticks = append(ticks, next)
+		if len(ticks) > entry.options.MaxCatchUp {
+			ticks = ticks[1:]
+		}
+	}
+
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	latest := ticks[len(ticks)-1]
+
+	var runs []time.Time
+	switch entry.options.Missed {
+	case options.MissedCatchUpAll:
+		runs = ticks
+
+	case options.MissedCatchUpOnce:
+		runs = []time.Time{latest}
+
+	default:
+		for _, tick := range ticks {
+			if now.Sub(tick) <= entry.options.MissedThreshold {
+				runs = append(runs, tick)
+			}
+		}
+	}
+
+	if skipped := len(ticks) - len(runs); skipped > 0 {
+		s.logger.Warn("Skipping '%d' missed runs of schedule '%s'", skipped, entry.name)
+	}
+
+	session, exist := sessions[entry.suffix]
+	if !exist {
+		created, err := s.client.Session(entry.suffix)
+		if err != nil {
+			return fmt.Errorf("failed to create session for suffix '%s': %w", entry.suffix, err)
+		}
+
+		session = created
+		sessions[entry.suffix] = session
+	}
+
+	for _, tick := range runs {
+		ev := entry.template
+		ev.ID = runID(entry.name, tick)
+		ev.Time = now
+		ev.Metadata = maps.Clone(entry.template.Metadata)
+		if ev.Metadata == nil {
+			ev.Metadata = event.Metadata{}
+		}
+
+		ev.Metadata[event.MetadataSchedule] = entry.name
+		ev.Metadata[event.MetadataScheduledAt] = tick.Format(time.RFC3339)
+
+		s.logger.Info("Submitting run '%s' of schedule '%s'", ev.ID, entry.name)
+
+		if err := session.GetWriter(ev.Priority.Topic()).WriteEvent(ctx, &ev); err != nil {
+			return fmt.Errorf("failed to submit run: %w", err)
+		}
+	}
+
+	if err := writer.WriteEvent(ctx, &record{
+		Node:     node,
+		Time:     now,
+		Kind:     recordTick,
+		Schedule: entry.name,
+		Tick:     latest,
+	}); err != nil {
+		return fmt.Errorf("failed to write tick: %w", err)
+	}
+
+	s.mutex.Lock()
+	if latest.After(entry.last) {
+		entry.last = latest
+	}
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// catchUpFrom returns the time after which missed runs are considered, which is limited to the
+// number of runs that may be caught up, measured with the interval between the upcoming runs.
+func catchUpFrom(schedule Schedule, last, now time.Time, max int) time.Time {
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return last
+	}
+
+	interval := schedule.Next(next).Sub(next)
+	if interval <= 0 || interval > time.Duration(math.MaxInt64)/time.Duration(max+1) {
+		return last
+	}
+
+	if from := now.Add(-interval * time.Duration(max+1)); from.After(last) {
+		return from
+	}
+	return last
+}
+
+// runID derives the task ID from the schedule and run time, so that a run
+// submitted twice after a leader change can be identified as the same task.
+func runID(name string, tick time.Time) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", name, tick.UnixNano())))
+	return fmt.Sprintf("%x", sum[:16])
+}
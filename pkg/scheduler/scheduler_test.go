@@ -0,0 +1,220 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func newScheduler(t *testing.T) *Scheduler {
+	t.Helper()
+
+	c, err := memory.NewMemory(memory.NewBroker(), options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+	t.Cleanup(func() { c.Cleanup() })
+
+	s, err := NewSchedulerWithTransport(c, options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+	return s
+}
+
+func session(t *testing.T, s *Scheduler, suffix string) transport.Session {
+	t.Helper()
+
+	session, err := s.client.Session(suffix)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	return session
+}
+
+// fireEntry fires the schedule once and returns the scheduled times of all submitted runs.
+func fireEntry(t *testing.T, s *Scheduler, entry *scheduleEntry, now time.Time) []time.Time {
+	t.Helper()
+
+	writer := session(t, s, "scheduler").GetWriter("events.leader")
+	if err := s.fire(context.Background(), "node", now, entry, now, writer, make(map[string]transport.Session)); err != nil {
+		t.Fatalf("failed to fire schedule: %v", err)
+	}
+
+	var runs []time.Time
+
+	submits := session(t, s, entry.suffix)
+	if err := submits.Scan(context.Background(), event.PriorityNormal.Topic(), func(msg transport.Message) error {
+		ev := &event.SubmitEvent{}
+		if err := submits.Decode(msg, ev); err != nil {
+			return err
+		}
+
+		tick, err := time.Parse(time.RFC3339, ev.Metadata[event.MetadataScheduledAt])
+		if err != nil {
+			return err
+		}
+		if ev.ID != runID(entry.name, tick) {
+			t.Fatalf("expected run ID '%s', got '%s'", runID(entry.name, tick), ev.ID)
+		}
+
+		runs = append(runs, tick)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to scan submitted runs: %v", err)
+	}
+
+	return runs
+}
+
+func TestRegisterRejectsScheduleNeverFiring(t *testing.T) {
+	s := newScheduler(t)
+
+	never, err := Cron("0 0 31 2 *")
+	if err != nil {
+		t.Fatalf("failed to parse expression: %v", err)
+	}
+	if err := s.Register("never", "reports", never, event.SubmitEvent{}); err == nil {
+		t.Fatal("expected schedule without any run to be rejected")
+	}
+
+	daily, _ := Cron("@daily")
+	if err := s.Register("daily", "reports", daily, event.SubmitEvent{}); err != nil {
+		t.Fatalf("failed to register schedule: %v", err)
+	}
+}
+
+func TestFireMissedPolicies(t *testing.T) {
+	schedule, _ := Every(time.Minute * 10)
+
+	// Four runs have been missed since the last run, the latest one five minutes ago
+	last := time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 1, 1, 11, 45, 0, 0, time.UTC)
+	at := func(minute int) time.Time {
+		return time.Date(2026, 1, 1, 11, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []options.ScheduleOption
+		expected []time.Time
+	}{
+		{name: "skip", expected: nil},
+		{name: "threshold", opts: []options.ScheduleOption{options.WithMissedThreshold(time.Minute * 20)}, expected: []time.Time{at(30), at(40)}},
+		{name: "once", opts: []options.ScheduleOption{options.WithMissedPolicy(options.MissedCatchUpOnce)}, expected: []time.Time{at(40)}},
+		{name: "all", opts: []options.ScheduleOption{options.WithMissedPolicy(options.MissedCatchUpAll)}, expected: []time.Time{at(10), at(20), at(30), at(40)}},
+		{name: "limited", opts: []options.ScheduleOption{options.WithMissedPolicy(options.MissedCatchUpAll), options.WithMaxCatchUp(2)}, expected: []time.Time{at(30), at(40)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newScheduler(t)
+			if err := s.Register("cleanup", "cleanup", schedule, event.SubmitEvent{}, test.opts...); err != nil {
+				t.Fatalf("failed to register schedule: %v", err)
+			}
+
+			entry := s.entries["cleanup"]
+			entry.last = last
+
+			runs := fireEntry(t, s, entry, now)
+			if len(runs) != len(test.expected) {
+				t.Fatalf("expected runs %v, got %v", test.expected, runs)
+			}
+			for i := range runs {
+				if !runs[i].Equal(test.expected[i]) {
+					t.Fatalf("expected runs %v, got %v", test.expected, runs)
+				}
+			}
+
+			// Missed runs are only considered once, even if they have been skipped
+			if !entry.last.Equal(at(40)) {
+				t.Fatalf("expected last run at '%v', got '%v'", at(40), entry.last)
+			}
+			if runs := fireEntry(t, s, entry, now); len(runs) != len(test.expected) {
+				t.Fatalf("expected no further runs, got %v", runs)
+			}
+		})
+	}
+}
+
+func TestLeaderIsOldestReplicaWithLease(t *testing.T) {
+	s := newScheduler(t)
+	now := time.Now()
+
+	if !s.leader("b", now, now) {
+		t.Fatal("expected single replica to be leader")
+	}
+	if s.leader("b", now.Add(-leaseTimeout*2), now) {
+		t.Fatal("expected replica with lapsed lease to not be leader")
+	}
+
+	s.peers["a"] = now
+	s.peers["c"] = now
+	if s.leader("b", now, now) {
+		t.Fatal("expected older replica to be leader")
+	}
+	if !s.leader("a", now, now) {
+		t.Fatal("expected oldest replica to be leader")
+	}
+
+	// The older replica has stopped sending heartbeats
+	s.peers["a"] = now.Add(-leaseTimeout * 2)
+	if !s.leader("b", now, now) {
+		t.Fatal("expected replica to take over once the lease of the older replica has lapsed")
+	}
+}
+
+func TestLatestClaimFencesPreviousLeader(t *testing.T) {
+	s := newScheduler(t)
+	schedule, _ := Every(time.Minute)
+	if err := s.Register("cleanup", "cleanup", schedule, event.SubmitEvent{}); err != nil {
+		t.Fatalf("failed to register schedule: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leader := session(t, s, "scheduler")
+	go s.processRecords(ctx, leader)
+
+	first := time.Now()
+	second := first.Add(time.Second)
+	tick := first.Truncate(time.Minute)
+
+	writer := leader.GetWriter("events.leader")
+	for _, rec := range []*record{
+		{Node: "a", Time: first, Kind: recordClaim},
+		{Node: "a", Time: first, Kind: recordTick, Schedule: "cleanup", Tick: tick},
+		{Node: "b", Time: second, Kind: recordClaim},
+	} {
+		if err := writer.WriteEvent(ctx, rec); err != nil {
+			t.Fatalf("failed to write record: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second * 5)
+	for !s.holdsClaim("b", second) {
+		if time.Now().After(deadline) {
+			t.Fatal("claim of the new leader has not been observed")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	if s.holdsClaim("a", first) {
+		t.Fatal("expected previous leader to be fenced off")
+	}
+
+	// Ticks of the previous leader are observed before the claim of the new leader
+	s.mutex.RLock()
+	last := s.entries["cleanup"].last
+	s.mutex.RUnlock()
+
+	if !last.Equal(tick) {
+		t.Fatalf("expected last run at '%v', got '%v'", tick, last)
+	}
+}
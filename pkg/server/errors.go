@@ -2,7 +2,19 @@ package server
 
 import (
 	"errors"
+	"fmt"
 	"sync"
+	"time"
+)
+
+var (
+	ErrDeadlineExceeded = errors.New("task deadline exceeded")
+	ErrTimeout          = errors.New("task timed out")
+	ErrLost             = errors.New("task lost")
+	ErrShutdown         = errors.New("worker shut down")
+	ErrNoRoute          = errors.New("no handler registered for routing key")
+	ErrInvalidPayload   = errors.New("invalid payload")
+	ErrPanic            = errors.New("handler panic")
 )
 
 type Errors struct {
@@ -30,3 +42,41 @@ func (e *Errors) Errors() error {
 
 	return errors.Join(e.errors...)
 }
+
+// PermanentError marks a handler error as non-retryable.
+type PermanentError struct {
+	Err error
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{
+		Err: err,
+	}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned once the timeout or deadline of a task has been exceeded.
+type TimeoutError struct {
+	Timeout time.Duration
+	Elapsed time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%v after '%v'", e.Err, e.Elapsed)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
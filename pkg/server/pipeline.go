@@ -2,23 +2,38 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	"github.com/mwantia/asynk/internal/kafka"
+	"github.com/mwantia/asynk/pkg/codec"
 	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
 type Pipeline struct {
 	logger  log.LogWrapper
-	session *kafka.Session
+	session transport.Session
+	codecs  *codec.Registry
+	codec   string
 	submit  *event.SubmitEvent
+
+	// Commits the offset of the submit event, unless it has been held back
+	ack  func()
+	held bool
+
+	// Terminal status emitted for the task, if any
+	terminal *event.StatusEvent
 }
 
 func (p *Pipeline) Submit() *event.SubmitEvent {
 	return p.submit // Simply return the privately stored submit event
 }
 
+func (p *Pipeline) Logger() log.LogWrapper {
+	return p.logger
+}
+
 func (p *Pipeline) Status(ctx context.Context, ev *event.StatusEvent) error {
 	p.logger.Debug("Updating status for task '%s' to '%s'", p.submit.ID, ev.Status)
 
@@ -35,7 +50,69 @@ func (p *Pipeline) Status(ctx context.Context, ev *event.StatusEvent) error {
 		ev.ID = p.submit.ID
 	}
 
-	return writer.WriteEvent(ctx, ev)
+	if err := writer.WriteEvent(ctx, ev); err != nil {
+		return err
+	}
+
+	if ev.Status.IsTerminal() {
+		p.terminal = ev
+		p.releaseUnique(ctx)
+	}
+	return nil
+}
+
+// releaseUnique gives up the unique key of the task, so that it can be submitted again.
+func (p *Pipeline) releaseUnique(ctx context.Context) {
+	key := p.submit.Metadata[event.MetadataUniqueKey]
+	if key == "" {
+		return
+	}
+
+	writer := p.session.GetWriter("events.unique")
+
+	if err := writer.WriteEvent(ctx, &event.UniqueEvent{
+		Key:    key,
+		ID:     p.submit.ID,
+		Time:   time.Now(),
+		Action: event.UniqueRelease,
+	}); err != nil {
+		p.logger.Warn("Failed to release unique key '%s' for task '%s': %v", key, p.submit.ID, err)
+	}
+}
+
+// Progress reports the running status of the task with the given payload,
+// encoded with the same content type as the payload of the task.
+func (p *Pipeline) Progress(ctx context.Context, payload any) error {
+	c, err := p.payloadCodec()
+	if err != nil {
+		return err
+	}
+
+	if !codec.IsJSON(c.ContentType()) && codec.IsJSON(p.codec) {
+		// Binary payloads cannot be embedded into json events without being encoded again
+		return fmt.Errorf("progress payload with content type '%s' requires a binary codec", c.ContentType())
+	}
+
+	data, err := c.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress payload: %w", err)
+	}
+
+	return p.Status(ctx, &event.StatusEvent{
+		Status:      event.StatusRunning,
+		ContentType: c.ContentType(),
+		Payload:     data,
+	})
+}
+
+// payloadCodec returns the codec for the content type of the task payload, which defaults to json.
+func (p *Pipeline) payloadCodec() (codec.Codec, error) {
+	contentType := p.submit.ContentType
+	if contentType == "" {
+		contentType = codec.ContentTypeJSON
+	}
+
+	return p.codecs.Get(contentType)
 }
 
 func (p *Pipeline) Done(ctx context.Context, s event.Status) error {
@@ -44,3 +121,17 @@ func (p *Pipeline) Done(ctx context.Context, s event.Status) error {
 		Status: s,
 	})
 }
+
+// hold prevents the offset from being committed once processing has finished, so that the task is delivered
+// again after the worker restarts. As no later offset of the partition can be committed either,
+// tasks are only held once they could not be handed off before the worker stops.
+func (p *Pipeline) hold() {
+	p.held = true
+}
+
+// release commits the offset, unless it has been held back.
+func (p *Pipeline) release() {
+	if !p.held && p.ack != nil {
+		p.ack()
+	}
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mwantia/asynk/pkg/codec"
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func newPipeline(t *testing.T, contentType string, submit *event.SubmitEvent) *Pipeline {
+	t.Helper()
+
+	c, err := memory.NewMemory(memory.NewBroker(), options.WithLogLevel("ERROR"), options.WithCodec(contentType))
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+
+	s, err := NewServerWithTransport(c, options.WithLogLevel("ERROR"), options.WithCodec(contentType))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	session, err := c.Session("test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	return &Pipeline{
+		logger:  s.logger,
+		session: session,
+		codecs:  s.options.Codecs,
+		codec:   s.options.Codec,
+		submit:  submit,
+	}
+}
+
+func TestProgressRequiresBinaryCodec(t *testing.T) {
+	submit := &event.SubmitEvent{ID: "a", ContentType: codec.ContentTypeMsgpack}
+
+	if err := newPipeline(t, codec.ContentTypeJSON, submit).Progress(context.Background(), map[string]int{"done": 1}); err == nil {
+		t.Fatal("expected binary progress payload to be rejected with a json codec")
+	}
+
+	if err := newPipeline(t, codec.ContentTypeMsgpack, submit).Progress(context.Background(), map[string]int{"done": 1}); err != nil {
+		t.Fatalf("expected binary progress payload with a msgpack codec, got: %v", err)
+	}
+}
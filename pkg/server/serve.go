@@ -3,18 +3,29 @@ package server
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 	"sync"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
 )
 
+// ServeMux registers handlers per suffix when served by the server. Used as handler itself,
+// it routes every task by its type (or routing key) to the handler with the best matching pattern.
 type ServeMux struct {
-	mutex    sync.RWMutex
-	handlers map[string]serveMuxEntry
+	mutex       sync.RWMutex
+	handlers    map[string]serveMuxEntry
+	middlewares []Middleware
+	fallback    Handler
 }
 
 type serveMuxEntry struct {
 	handler Handler
 	topic   string
+	options options.RouteOptions
+	// Route options are ignored by a mux used as handler, so they are tracked to reject them when served
+	routed bool
 }
 
 type Handler interface {
@@ -27,13 +38,16 @@ func (fn HandlerFunc) ProcessPipeline(ctx context.Context, p *Pipeline) error {
 	return fn(ctx, p)
 }
 
+// Middleware wraps a handler to run code before and after every call of the handler.
+type Middleware func(Handler) Handler
+
 func NewServeMux() *ServeMux {
 	return &ServeMux{
 		handlers: make(map[string]serveMuxEntry),
 	}
 }
 
-func (mux *ServeMux) Handle(topic string, handler Handler) error {
+func (mux *ServeMux) Handle(topic string, handler Handler, opts ...options.RouteOption) error {
 	mux.mutex.Lock()
 	defer mux.mutex.Unlock()
 
@@ -43,35 +57,144 @@ func (mux *ServeMux) Handle(topic string, handler Handler) error {
 	if strings.TrimSpace(topic) == "" {
 		return fmt.Errorf("invalid suffix")
 	}
+	if _, err := path.Match(topic, ""); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
 
 	if _, exist := mux.handlers[topic]; exist {
 		return fmt.Errorf("suffix already registered")
 	}
 
+	options := options.DefaultRouteOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return fmt.Errorf("failed to apply route option: %w", err)
+		}
+	}
+
 	mux.handlers[topic] = serveMuxEntry{
 		handler: handler,
 		topic:   topic,
+		options: options,
+		routed:  len(opts) > 0,
 	}
 
 	return nil
 }
 
-func (mux *ServeMux) HandleFunc(topic string, handler func(context.Context, *Pipeline) error) error {
+func (mux *ServeMux) HandleFunc(topic string, handler func(context.Context, *Pipeline) error, opts ...options.RouteOption) error {
 	if handler == nil {
 		return fmt.Errorf("invalid handler")
 	}
 
-	return mux.Handle(topic, HandlerFunc(handler))
+	return mux.Handle(topic, HandlerFunc(handler), opts...)
+}
+
+// Use adds middlewares, which wrap the handlers of all routes. The first middleware is the outermost one.
+func (mux *ServeMux) Use(middleware ...Middleware) {
+	mux.mutex.Lock()
+	defer mux.mutex.Unlock()
+
+	mux.middlewares = append(mux.middlewares, middleware...)
+}
+
+// wrap applies all middlewares to the handler.
+func (mux *ServeMux) wrap(handler Handler) Handler {
+	mux.mutex.RLock()
+	defer mux.mutex.RUnlock()
+
+	for i := len(mux.middlewares) - 1; i >= 0; i-- {
+		handler = mux.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// HandleFallback registers the handler used for tasks without any matching pattern.
+func (mux *ServeMux) HandleFallback(handler Handler) error {
+	mux.mutex.Lock()
+	defer mux.mutex.Unlock()
+
+	if handler == nil {
+		return fmt.Errorf("invalid handler")
+	}
+
+	mux.fallback = handler
+	return nil
 }
 
+// ProcessPipeline routes the task to the handler matching its routing key, which is the
+// routing key metadata or otherwise the type of the task. Patterns support wildcards like
+// 'image.*'; An exact match is preferred, followed by the longest matching pattern.
 func (mux *ServeMux) ProcessPipeline(ctx context.Context, p *Pipeline) error {
+	key := p.Submit().Type.String()
+	if value, exist := p.Submit().Metadata[event.MetadataRoutingKey]; exist {
+		key = value
+	}
+
+	handler := mux.match(key)
+	if handler == nil {
+		return Permanent(fmt.Errorf("%w '%s'", ErrNoRoute, key))
+	}
+
+	return mux.wrap(handler).ProcessPipeline(ctx, p)
+}
+
+// validateNested returns an error if any route of the mux used as handler, or of its own nested muxes,
+// has been registered with route options, which only apply to the suffixes passed to the server.
+func (mux *ServeMux) validateNested() error {
+	mux.mutex.RLock()
+	defer mux.mutex.RUnlock()
+
+	for pattern, entry := range mux.handlers {
+		if entry.routed {
+			return fmt.Errorf("route options of '%s' are ignored by a mux registered as handler", pattern)
+		}
+		if nested, ok := entry.handler.(*ServeMux); ok {
+			if err := nested.validateNested(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if nested, ok := mux.fallback.(*ServeMux); ok {
+		return nested.validateNested()
+	}
+
+	return nil
+}
+
+// isPattern reports whether the topic contains any wildcard of path.Match.
+func isPattern(topic string) bool {
+	return strings.ContainsAny(topic, `*?[\`)
+}
+
+// match returns the handler of the best matching pattern or the fallback handler.
+func (mux *ServeMux) match(key string) Handler {
 	mux.mutex.RLock()
 	defer mux.mutex.RUnlock()
 
-	handler, exist := mux.handlers[""]
-	if exist {
-		return handler.handler.ProcessPipeline(ctx, p)
+	if entry, exist := mux.handlers[key]; exist {
+		return entry.handler
+	}
+
+	best := ""
+	var handler Handler
+
+	for pattern, entry := range mux.handlers {
+		if matched, _ := path.Match(pattern, key); !matched {
+			continue
+		}
+
+		if handler == nil || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best = pattern
+			handler = entry.handler
+		}
+	}
+
+	if handler == nil {
+		return mux.fallback
 	}
 
-	return fmt.Errorf("topic not found")
+	return handler
 }
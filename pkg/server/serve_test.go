@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	c, err := memory.NewMemory(memory.NewBroker(), options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+
+	s, err := NewServerWithTransport(c, options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	return s
+}
+
+// route registers a handler on the mux, which reports the pattern it has been registered with.
+func route(t *testing.T, mux *ServeMux, pattern string, opts ...options.RouteOption) {
+	t.Helper()
+
+	if err := mux.HandleFunc(pattern, func(ctx context.Context, p *Pipeline) error {
+		return errors.New(pattern)
+	}, opts...); err != nil {
+		t.Fatalf("failed to register '%s': %v", pattern, err)
+	}
+}
+
+func TestServeMutexRejectsPatternSuffix(t *testing.T) {
+	s := newTestServer(t)
+
+	mux := NewServeMux()
+	if err := mux.HandleFunc("image.*", func(ctx context.Context, p *Pipeline) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to register pattern: %v", err)
+	}
+
+	if err := s.ServeMutex(context.Background(), mux); err == nil {
+		t.Fatal("expected pattern suffix to be rejected")
+	}
+}
+
+func TestServeMutexRejectsNestedRouteOptions(t *testing.T) {
+	s := newTestServer(t)
+
+	nested := NewServeMux()
+	route(t, nested, "image.*", options.WithConcurrency(4))
+
+	mux := NewServeMux()
+	if err := mux.Handle("media", nested); err != nil {
+		t.Fatalf("failed to register nested mux: %v", err)
+	}
+
+	if err := s.ServeMutex(context.Background(), mux); err == nil {
+		t.Fatal("expected route options of the nested mux to be rejected")
+	}
+}
+
+func TestServeMuxMatchPrecedence(t *testing.T) {
+	mux := NewServeMux()
+	route(t, mux, "image.resize")
+	route(t, mux, "image.*")
+	route(t, mux, "image.re*")
+	if err := mux.HandleFallback(HandlerFunc(func(ctx context.Context, p *Pipeline) error {
+		return errors.New("fallback")
+	})); err != nil {
+		t.Fatalf("failed to register fallback: %v", err)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{key: "image.resize", expected: "image.resize"},
+		{key: "image.rescale", expected: "image.re*"},
+		{key: "image.crop", expected: "image.*"},
+		{key: "video.encode", expected: "fallback"},
+	}
+
+	for _, test := range tests {
+		p := &Pipeline{submit: &event.SubmitEvent{ID: "a", Type: event.EventType(test.key)}}
+		if err := mux.ProcessPipeline(context.Background(), p); err == nil || err.Error() != test.expected {
+			t.Fatalf("expected '%s' to be routed to '%s', got: %v", test.key, test.expected, err)
+		}
+	}
+}
+
+func TestServeMuxWithoutRoute(t *testing.T) {
+	mux := NewServeMux()
+	route(t, mux, "image.*")
+
+	// The routing key is preferred over the type of the task
+	p := &Pipeline{submit: &event.SubmitEvent{
+		ID:       "a",
+		Type:     "image.resize",
+		Metadata: map[string]string{event.MetadataRoutingKey: "video.encode"},
+	}}
+
+	err := mux.ProcessPipeline(context.Background(), p)
+	if !errors.Is(err, ErrNoRoute) {
+		t.Fatalf("expected '%v', got: %v", ErrNoRoute, err)
+	}
+
+	var perr *PermanentError
+	if !errors.As(err, &perr) {
+		t.Fatal("expected a task without route to fail permanently")
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mwantia/asynk/pkg/options"
+)
+
+// HandleTyped registers a handler receiving the payload of every task, decoded by its content type.
+// Tasks with a payload that cannot be decoded fail without being retried.
+func HandleTyped[T any](mux *ServeMux, topic string, handler func(context.Context, *Pipeline, T) error, opts ...options.RouteOption) error {
+	if handler == nil {
+		return fmt.Errorf("invalid handler")
+	}
+
+	return mux.Handle(topic, HandlerFunc(func(ctx context.Context, p *Pipeline) error {
+		var payload T
+		if len(p.Submit().Payload) > 0 {
+			c, err := p.payloadCodec()
+			if err != nil {
+				return Permanent(fmt.Errorf("%w: %w", ErrInvalidPayload, err))
+			}
+
+			if err := c.Unmarshal(p.Submit().Payload, &payload); err != nil {
+				return Permanent(fmt.Errorf("%w: %w", ErrInvalidPayload, err))
+			}
+		}
+
+		return handler(ctx, p, payload)
+	}), opts...)
+}
@@ -11,17 +11,25 @@ import (
 	basic "github.com/mwantia/asynk/internal/log"
 	"github.com/mwantia/asynk/pkg/log"
 	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
 type Server struct {
+	options options.ClientOptions
 	logger  log.LogWrapper
 	mutex   sync.RWMutex
-	client  *kafka.Client
+	client  transport.Transport
 	workers map[string]*Worker
 	active  atomic.Bool
 }
 
 func NewServer(opts ...options.ClientOption) (*Server, error) {
+	return NewServerWithTransport(nil, opts...)
+}
+
+// NewServerWithTransport creates a server using the provided transport.
+// If no transport is provided, a kafka transport is created from the options.
+func NewServerWithTransport(t transport.Transport, opts ...options.ClientOption) (*Server, error) {
 	options := options.DefaultClientOptions()
 	for _, opt := range opts {
 		if err := opt(&options); err != nil {
@@ -29,6 +37,10 @@ func NewServer(opts ...options.ClientOption) (*Server, error) {
 		}
 	}
 
+	if err := transport.ValidateCodec(options.Codecs, options.Codec); err != nil {
+		return nil, fmt.Errorf("invalid codec: %w", err)
+	}
+
 	var logger log.LogWrapper
 
 	if options.Logger != nil {
@@ -39,14 +51,18 @@ func NewServer(opts ...options.ClientOption) (*Server, error) {
 		logger = l.Named("asynk/server")
 	}
 
-	client, err := kafka.NewKafka(options, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	if t == nil {
+		k, err := kafka.NewKafka(options, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka client: %w", err)
+		}
+		t = k
 	}
 
 	return &Server{
+		options: options,
 		logger:  logger,
-		client:  client,
+		client:  t,
 		workers: make(map[string]*Worker),
 	}, nil
 }
@@ -57,6 +73,18 @@ func (s *Server) ServeMutex(ctx context.Context, mux *ServeMux) error {
 	}
 	defer s.active.Store(false)
 
+	// Every suffix is used as a topic, so patterns are only supported by nested muxes
+	for suffix, entry := range mux.handlers {
+		if isPattern(suffix) {
+			return fmt.Errorf("invalid suffix '%s': patterns are only supported by a mux registered as handler of a suffix", suffix)
+		}
+		if nested, ok := entry.handler.(*ServeMux); ok {
+			if err := nested.validateNested(); err != nil {
+				return fmt.Errorf("invalid suffix '%s': %w", suffix, err)
+			}
+		}
+	}
+
 	s.logger.Info("Starting server with '%d' handlers", len(mux.handlers))
 
 	var wg sync.WaitGroup
@@ -67,7 +95,9 @@ func (s *Server) ServeMutex(ctx context.Context, mux *ServeMux) error {
 
 		s.logger.Debug("Starting worker for topic '%s'", suffix)
 
-		go func(suffix string, handler Handler) {
+		handler.handler = mux.wrap(handler.handler)
+
+		go func(suffix string, entry serveMuxEntry) {
 			defer wg.Done()
 
 			for {
@@ -77,25 +107,39 @@ func (s *Server) ServeMutex(ctx context.Context, mux *ServeMux) error {
 					return
 
 				default:
-					if err := s.runWorker(ctx, suffix, handler); err != nil {
+					if err := s.runWorker(ctx, suffix, entry); err != nil {
 						s.logger.Warn("Error during working execution: %v", err)
-						time.Sleep(time.Second * 10)
+						select {
+						case <-ctx.Done():
+						case <-time.After(time.Second * 10):
+						}
 						continue
 					}
 				}
 			}
-		}(suffix, handler.handler)
+		}(suffix, handler)
 	}
 
 	s.logger.Info("Server started successfully, waiting for context...")
 	<-ctx.Done()
 
+	// Running pipelines may finish until the shutdown timeout has been reached
+	shutdown, cancel := context.WithTimeout(context.Background(), s.options.ShutdownTimeout)
+	defer cancel()
+
 	s.mutex.Lock()
+	var shutdowns sync.WaitGroup
 	for _, worker := range s.workers {
-		if err := worker.Shutdown(ctx); err != nil {
-			errs.Add(fmt.Errorf("failed to shutdown worker: %w", err))
-		}
+		shutdowns.Add(1)
+		go func() {
+			defer shutdowns.Done()
+
+			if err := worker.Shutdown(shutdown); err != nil {
+				errs.Add(fmt.Errorf("failed to shutdown worker: %w", err))
+			}
+		}()
 	}
+	shutdowns.Wait()
 	// Reset list of running workers
 	s.workers = make(map[string]*Worker)
 	s.mutex.Unlock()
@@ -104,7 +148,7 @@ func (s *Server) ServeMutex(ctx context.Context, mux *ServeMux) error {
 	wg.Wait()
 
 	if err := s.client.Cleanup(); err != nil {
-		s.logger.Error("Failed to cleanup Kafka client: %v", err)
+		s.logger.Error("Failed to cleanup transport: %v", err)
 		errs.Add(fmt.Errorf("failed to perform client cleanup: %w", err))
 	}
 
@@ -5,18 +5,24 @@ import (
 	"fmt"
 )
 
-func (s *Server) runWorker(ctx context.Context, suffix string, handler Handler) error {
+func (s *Server) runWorker(ctx context.Context, suffix string, entry serveMuxEntry) error {
 	session, err := s.client.Session(suffix)
 	if err != nil {
 		return fmt.Errorf("failed to create session for suffix '%s': %w", suffix, err)
 	}
 
-	worker, err := NewWorker(s, session)
+	worker, err := NewWorker(s, session, entry.options)
 	if err != nil {
 		return fmt.Errorf("failed to create worker for suffix '%s': %w", suffix, err)
 	}
 
+	// Registered under the same lock the shutdown uses to collect all workers,
+	// so that no worker is started once the shutdown has already begun
 	s.mutex.Lock()
+	if ctx.Err() != nil {
+		s.mutex.Unlock()
+		return nil
+	}
 	s.workers[suffix] = worker
 	s.mutex.Unlock()
 
@@ -31,7 +37,8 @@ func (s *Server) runWorker(ctx context.Context, suffix string, handler Handler)
 			}
 		}()
 
-		if err := worker.Process(ctx, handler); err != nil {
+		// Workers are only stopped by their shutdown, so that running pipelines can be drained
+		if err := worker.Process(context.WithoutCancel(ctx), entry.handler); err != nil {
 			done <- err
 		}
 	}()
@@ -39,7 +46,7 @@ func (s *Server) runWorker(ctx context.Context, suffix string, handler Handler)
 	select {
 	case <-ctx.Done():
 		s.logger.Info("Context cancelled for worker '%s'", suffix)
-		return ctx.Err()
+		return nil
 
 	case err := <-done:
 		s.mutex.Lock()
@@ -4,32 +4,91 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
-	"github.com/mwantia/asynk/internal/kafka"
+	"github.com/mwantia/asynk/pkg/codec"
+	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
+// Pipelines cut off during shutdown get a short grace period to return after their context has been cancelled
+const shutdownGrace = time.Second * 5
+
 type Worker struct {
 	logger  log.LogWrapper
-	session *kafka.Session
+	session transport.Session
+	options options.RouteOptions
+	codecs  *codec.Registry
+	codec   string
+
+	mutex    sync.Mutex
+	inflight map[string]context.CancelCauseFunc
+	controls map[string]*event.ControlEvent
+	slots    chan struct{}
 
 	running sync.WaitGroup
-	cancel  context.CancelFunc
+	cancel  context.CancelCauseFunc
+	drain   context.CancelFunc
+	closed  bool
 }
 
-func NewWorker(server *Server, session *kafka.Session) (*Worker, error) {
+func NewWorker(server *Server, session transport.Session, options options.RouteOptions) (*Worker, error) {
 	return &Worker{
 		logger:  server.logger.Named("asynk/worker"),
 		session: session,
+		options: options,
+		codecs:  server.options.Codecs,
+		codec:   server.options.Codec,
+
+		inflight: make(map[string]context.CancelCauseFunc),
+		controls: make(map[string]*event.ControlEvent),
+		slots:    make(chan struct{}, max(1, options.Concurrency)),
 	}, nil
 }
 
+// Shutdown stops fetching new tasks and waits for all running pipelines to finish, until the context is done.
+// Pipelines still running afterwards are cancelled and their tasks are released for redelivery.
 func (w *Worker) Shutdown(ctx context.Context) error {
-	if w.cancel != nil {
-		w.cancel()
+	w.mutex.Lock()
+	cancel, drain := w.cancel, w.drain
+	w.closed = true
+	w.mutex.Unlock()
+
+	w.logger.Info("Draining worker...")
+
+	if drain != nil {
+		drain()
+	}
+
+	// All slots are free once every running pipeline has finished
+	drained := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for range cap(w.slots) {
+			select {
+			case w.slots <- struct{}{}:
+			case <-stop:
+				return
+			}
+		}
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		w.logger.Info("All running pipelines have finished")
+
+	case <-ctx.Done():
+		w.logger.Warn("Shutdown timeout exceeded; Cutting off running pipelines...")
 	}
 
-	w.logger.Info("Starting worker shutdown...")
+	if cancel != nil {
+		cancel(ErrShutdown)
+	}
 
 	done := make(chan struct{})
 	go func() {
@@ -42,8 +101,47 @@ func (w *Worker) Shutdown(ctx context.Context) error {
 		w.logger.Info("Worker shutdown complete")
 		return nil
 
-	case <-ctx.Done():
+	case <-time.After(shutdownGrace):
 		w.logger.Warn("Worker shutdown timeout exceeded")
 		return fmt.Errorf("shutdown timeout exceeded")
 	}
 }
+
+// cutOff releases a task cut off by the shutdown, so that it is delivered again.
+// The task is submitted again, so that its offset can be committed without blocking any later offset.
+func (w *Worker) cutOff(ctx context.Context, p *Pipeline) error {
+	w.logger.Warn("Task '%s' cut off by shutdown; Releasing for redelivery", p.submit.ID)
+
+	release, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownGrace)
+	defer cancel()
+
+	if err := w.handOff(release, p, func(ctx context.Context) error {
+		return w.session.GetWriter(p.submit.Priority.Topic()).WriteEvent(ctx, p.submit)
+	}); err != nil {
+		return fmt.Errorf("failed to release task: %w", err)
+	}
+
+	return nil
+}
+
+// handOff retries the write until it succeeds or the context is done. Tasks that could not be handed off
+// are held back, so that they are delivered again once the worker restarts.
+func (w *Worker) handOff(ctx context.Context, p *Pipeline, write func(context.Context) error) error {
+	for {
+		err := write(ctx)
+		if err == nil {
+			return nil
+		}
+
+		w.logger.Warn("Failed to hand off task '%s': %v", p.submit.ID, err)
+
+		select {
+		case <-ctx.Done():
+			p.hold()
+			return err
+
+		case <-time.After(time.Second * 2):
+			// Continue after a short delay
+		}
+	}
+}
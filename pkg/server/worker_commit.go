@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// commitTracker keeps track of fetched messages per partition, so that offsets
+// are only committed once all previously fetched messages have been processed.
+type commitTracker struct {
+	mutex   sync.Mutex
+	pending map[int][]*pendingOffset
+}
+
+type pendingOffset struct {
+	msg  transport.Message
+	done bool
+}
+
+func newCommitTracker() *commitTracker {
+	return &commitTracker{
+		pending: make(map[int][]*pendingOffset),
+	}
+}
+
+func (t *commitTracker) track(msg transport.Message) *pendingOffset {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	offset := &pendingOffset{
+		msg: msg,
+	}
+
+	t.pending[msg.Partition] = append(t.pending[msg.Partition], offset)
+	return offset
+}
+
+// complete marks the offset as done and returns the last message that can be committed, if any.
+func (t *commitTracker) complete(offset *pendingOffset) (transport.Message, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	offset.done = true
+
+	pending := t.pending[offset.msg.Partition]
+
+	var last *pendingOffset
+	for len(pending) > 0 && pending[0].done {
+		last = pending[0]
+		pending = pending[1:]
+	}
+
+	t.pending[offset.msg.Partition] = pending
+
+	if last == nil {
+		return transport.Message{}, false
+	}
+
+	return last.msg, true
+}
+
+// commit completes the offset and commits all processed messages in order.
+func (w *Worker) commit(ctx context.Context, reader transport.Reader, commits *commitTracker, offset *pendingOffset) {
+	msg, ok := commits.complete(offset)
+	if !ok {
+		return
+	}
+
+	// Processed messages are committed, even if the worker is shutting down
+	commit, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second*10)
+	defer cancel()
+
+	if err := reader.Commit(commit, msg); err != nil {
+		w.logger.Error("Failed to commit offset '%d' for partition '%d': %v", msg.Offset, msg.Partition, err)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+)
+
+const (
+	// Control events are only remembered as long as they are retained on the control topic
+	controlRetention = time.Hour * 24
+)
+
+var (
+	ErrArchived  = errors.New("task has been archived")
+	ErrCancelled = errors.New("task has been cancelled")
+)
+
+// processControl observes the control topic and applies control events to in-flight tasks.
+func (w *Worker) processControl(ctx context.Context) {
+	defer w.running.Done()
+
+	reader := w.session.GetReader("events.control", options.WithBroadcast())
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Debug("Control processing stopped")
+			return
+
+		default:
+			ev := &event.ControlEvent{}
+			if err := reader.ReadEvent(ctx, ev); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				w.logger.Warn("Error reading control event: %v", err)
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-time.After(time.Second * 2):
+					// Continue after a short delay
+				}
+				continue
+			}
+
+			w.handleControl(ev)
+		}
+	}
+}
+
+func (w *Worker) handleControl(ev *event.ControlEvent) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for id, control := range w.controls {
+		if time.Since(control.Time) > controlRetention {
+			delete(w.controls, id)
+		}
+	}
+
+	if time.Since(ev.Time) > controlRetention {
+		return
+	}
+
+	w.controls[ev.ID] = ev
+
+	if cancel, exist := w.inflight[ev.ID]; exist {
+		w.logger.Info("Applying control action '%s' to running task '%s'", ev.Action, ev.ID)
+		cancel(controlCause(ev))
+	}
+}
+
+// register marks the task as in-flight, unless a control event has already been registered for it.
+// Both happen under the same lock as handleControl, so that no control event arriving in between is lost.
+func (w *Worker) register(id string, cancel context.CancelCauseFunc) *event.ControlEvent {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if ctrl, exist := w.controls[id]; exist {
+		return ctrl
+	}
+
+	w.inflight[id] = cancel
+	return nil
+}
+
+func (w *Worker) unregister(id string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.inflight, id)
+}
+
+// control returns the control event registered for the task, if any.
+func (w *Worker) control(id string) *event.ControlEvent {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.controls[id]
+}
+
+// controlStatus reports the terminal status caused by the control event.
+func (w *Worker) controlStatus(ctx context.Context, p *Pipeline, ev *event.ControlEvent) error {
+	switch ev.Action {
+	case event.ActionArchive:
+		return p.Status(ctx, &event.StatusEvent{
+			Status: event.StatusArchived,
+			Metadata: event.Metadata{
+				event.MetadataArchiveReason: ev.Reason,
+			},
+		})
+
+	case event.ActionCancel:
+		return p.Done(ctx, event.StatusCancelled)
+	}
+
+	return fmt.Errorf("unknown control action '%s'", ev.Action)
+}
+
+func controlCause(ev *event.ControlEvent) error {
+	switch ev.Action {
+	case event.ActionArchive:
+		return fmt.Errorf("%w: %s", ErrArchived, ev.Reason)
+
+	case event.ActionCancel:
+		return ErrCancelled
+	}
+
+	return fmt.Errorf("unknown control action '%s'", ev.Action)
+}
+
+func isControlCause(err error) bool {
+	return errors.Is(err, ErrArchived) || errors.Is(err, ErrCancelled)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// deadLetter moves a task that exhausted its retries into the dead-letter topic.
+func (w *Worker) deadLetter(ctx context.Context, ev *event.SubmitEvent, cause error) error {
+	count, _ := w.retryState(ev)
+
+	attempts := append([]event.Attempt{}, ev.Attempts...)
+	attempts = append(attempts, event.Attempt{
+		Retry: count,
+		Time:  time.Now(),
+		Error: cause.Error(),
+	})
+
+	return w.writeDead(ctx, &event.DeadEvent{
+		ID:        ev.ID,
+		Payload:   ev.Payload,
+		Submit:    ev,
+		LastError: cause.Error(),
+		Attempts:  attempts,
+		Metadata:  ev.Metadata,
+	})
+}
+
+// deadLetterMessage moves a message that could not be decoded into the dead-letter topic.
+func (w *Worker) deadLetterMessage(ctx context.Context, derr *transport.DecodeError) error {
+	w.logger.Error("Failed to decode submit event '%s': %v", derr.Message.Key, derr.Err)
+
+	errs := w.writeDead(ctx, &event.DeadEvent{
+		ID:        derr.Message.Key,
+		Payload:   derr.Message.Value,
+		LastError: derr.Err.Error(),
+		Attempts: []event.Attempt{
+			{
+				Time:  time.Now(),
+				Error: derr.Err.Error(),
+			},
+		},
+	})
+
+	if derr.Message.Key != "" {
+		writer := w.session.GetWriter("events.status")
+		if err := writer.WriteEvent(ctx, &event.StatusEvent{
+			ID:     derr.Message.Key,
+			Time:   time.Now(),
+			Status: event.StatusFailed,
+			Metadata: event.Metadata{
+				event.MetadataLastError:   derr.Err.Error(),
+				event.MetadataLastAttempt: time.Now().Format(time.RFC3339),
+			},
+		}); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("failed to update status: %w", err))
+		}
+	}
+
+	return errs
+}
+
+func (w *Worker) writeDead(ctx context.Context, ev *event.DeadEvent) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	ev.Worker = w.session.GetID()
+	if host, err := os.Hostname(); err == nil {
+		ev.Host = host
+	}
+
+	w.logger.Warn("Moving task '%s' to dead-letter topic", ev.ID)
+
+	writer := w.session.GetWriter("events.dead")
+	if err := writer.WriteEvent(ctx, ev); err != nil {
+		return fmt.Errorf("failed to write dead-letter event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// delayTier is a topic on which delayed tasks wait for a fixed amount of time.
+// As every task on the same tier waits equally long, they become ready in the order they have been written.
+type delayTier struct {
+	topic string
+	wait  time.Duration
+}
+
+// delayTiers are ordered by their wait, so that tasks pass through fewer tiers the closer they are to being due.
+var delayTiers = []delayTier{
+	{topic: "events.delayed.1s", wait: time.Second},
+	{topic: "events.delayed.10s", wait: time.Second * 10},
+	{topic: "events.delayed.1m", wait: time.Minute},
+	{topic: "events.delayed.10m", wait: time.Minute * 10},
+	{topic: "events.delayed.1h", wait: time.Hour},
+	{topic: "events.delayed.1d", wait: time.Hour * 24},
+}
+
+// delay hands the submit event to the delayed topic until it is due.
+func (w *Worker) delay(ctx context.Context, ev *event.SubmitEvent) error {
+	w.logger.Debug("Delaying task '%s' until '%v'", ev.ID, ev.NotBefore)
+
+	writer := w.session.GetWriter("events.delayed")
+	if err := writer.WriteEvent(ctx, ev); err != nil {
+		return fmt.Errorf("failed to write delayed event: %w", err)
+	}
+
+	return nil
+}
+
+// processDelayed reads the delayed topic and all tiers, each with its own reader.
+// Every delayed task is moved on to the next tier or the submit topic and committed individually,
+// so neither a task waiting for a long time blocks the commits of others nor are all tasks held in memory.
+func (w *Worker) processDelayed(ctx context.Context) {
+	defer w.running.Done()
+
+	for _, tier := range delayTiers {
+		w.running.Add(1)
+		go func() {
+			defer w.running.Done()
+			w.processDelayTier(ctx, tier.topic, tier.wait)
+		}()
+	}
+
+	w.processDelayTier(ctx, "events.delayed", 0)
+	w.logger.Debug("Delayed processing stopped")
+}
+
+// processDelayTier waits until every task on the topic has spent the wait of the tier and then routes it.
+func (w *Worker) processDelayTier(ctx context.Context, topic string, wait time.Duration) {
+	reader := w.session.GetReader(topic)
+	commits := newCommitTracker()
+
+	for {
+		ev := &event.SubmitEvent{}
+		msg, err := reader.FetchEvent(ctx, ev)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			var derr *transport.DecodeError
+			if errors.As(err, &derr) {
+				if err := w.deadLetterMessage(ctx, derr); err != nil {
+					w.logger.Warn("Error processing delayed event: %v", err)
+				}
+				w.commit(ctx, reader, commits, commits.track(msg))
+				continue
+			}
+
+			w.logger.Warn("Error reading delayed event: %v", err)
+			if !sleep(ctx, time.Second*2) {
+				return
+			}
+			continue
+		}
+
+		offset := commits.track(msg)
+
+		if !sleep(ctx, time.Until(msg.Time.Add(wait))) {
+			return
+		}
+
+		for {
+			err := w.routeDelayed(ctx, ev)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				// Without a commit, the task is read again once the worker restarts
+				return
+			}
+
+			w.logger.Error("Failed to route delayed task '%s': %v", ev.ID, err)
+			if !sleep(ctx, time.Second*2) {
+				return
+			}
+		}
+
+		w.commit(ctx, reader, commits, offset)
+	}
+}
+
+// routeDelayed moves the task to the submit topic once it is due, or otherwise to the tier with the
+// longest wait it can still spend. Tasks due sooner than the shortest tier are held until they are due.
+func (w *Worker) routeDelayed(ctx context.Context, ev *event.SubmitEvent) error {
+	remaining := time.Until(ev.NotBefore)
+
+	topic := ""
+	for _, tier := range delayTiers {
+		if tier.wait <= remaining {
+			topic = tier.topic
+		}
+	}
+
+	if topic == "" {
+		if !sleep(ctx, remaining) {
+			return ctx.Err()
+		}
+
+		w.logger.Debug("Delayed task '%s' is due", ev.ID)
+		topic = ev.Priority.Topic()
+	}
+
+	if err := w.session.GetWriter(topic).WriteEvent(ctx, ev); err != nil {
+		return fmt.Errorf("failed to write delayed task to '%s': %w", topic, err)
+	}
+
+	return nil
+}
+
+// sleep waits for the duration and reports false if the context has been cancelled before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func TestDelayedTaskIsNotBlockedByLongerDelay(t *testing.T) {
+	broker := memory.NewBroker()
+
+	first := newFaultyTransport(t, broker)
+	rec := newRecorder()
+	startWorker(t, first, rec.handle)
+
+	submit(t, broker,
+		&event.SubmitEvent{ID: "a", NotBefore: time.Now().Add(time.Hour)},
+		&event.SubmitEvent{ID: "b", NotBefore: time.Now().Add(time.Millisecond * 200)},
+	)
+	rec.await(t, "b")
+
+	waitFor(t, func() bool {
+		return first.commits(event.PriorityNormal.Topic()) == 3 && first.commits("events.delayed") == 2
+	}, "offsets of the due task have not been committed")
+
+	first.kill()
+
+	second := newRecorder()
+	startWorker(t, newFaultyTransport(t, broker), second.handle)
+
+	submit(t, broker, &event.SubmitEvent{ID: "c"})
+	second.await(t, "c")
+
+	if n := second.count("b"); n != 0 {
+		t.Fatalf("expected task 'b' to not be processed again, got '%d' calls", n)
+	}
+}
@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// watchedTask is a running task observed by the watchdog. Only the first heartbeat carries the submit event,
+// so its offset is held until the task is done, while the offsets of later heartbeats are completed immediately.
+type watchedTask struct {
+	hb     *event.HeartbeatEvent
+	submit *event.SubmitEvent
+	seen   time.Time
+	offset *pendingOffset
+}
+
+// heartbeat emits heartbeats for the pipeline until the returned function is called,
+// which emits a final heartbeat marking the task as done.
+func (w *Worker) heartbeat(ctx context.Context, p *Pipeline) func() {
+	writer := w.session.GetWriter("events.heartbeat")
+	started := time.Now()
+
+	// Only the first heartbeat written carries the submit event
+	announced := false
+
+	beat := func(ctx context.Context, done bool) {
+		hb := &event.HeartbeatEvent{
+			ID:      p.submit.ID,
+			Worker:  w.session.GetID(),
+			Time:    time.Now(),
+			Started: started,
+			Done:    done,
+		}
+		if !done && !announced {
+			hb.Submit = p.submit
+		}
+
+		if err := writer.WriteEvent(ctx, hb); err != nil {
+			if ctx.Err() == nil {
+				w.logger.Warn("Failed to write heartbeat for task '%s': %v", p.submit.ID, err)
+			}
+			return
+		}
+
+		announced = announced || hb.Submit != nil
+	}
+
+	beat(ctx, false)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(w.options.Heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				beat(ctx, false)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		wg.Wait()
+
+		beat(context.WithoutCancel(ctx), true)
+	}
+}
+
+// processWatchdog observes the heartbeats of all running tasks and reports tasks as lost,
+// once their heartbeats stop. Heartbeats are shared between all workers of the same group,
+// offsets are only committed once a task is done, so that watched tasks survive a rebalance.
+func (w *Worker) processWatchdog(ctx context.Context) {
+	defer w.running.Done()
+
+	reader := w.session.GetReader("events.heartbeat")
+	commits := newCommitTracker()
+
+	var mutex sync.Mutex
+	watched := make(map[string]*watchedTask)
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+
+		for {
+			hb := &event.HeartbeatEvent{}
+			msg, err := reader.FetchEvent(ctx, hb)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				var derr *transport.DecodeError
+				if errors.As(err, &derr) {
+					w.commit(ctx, reader, commits, commits.track(msg))
+					continue
+				}
+
+				w.logger.Warn("Error reading heartbeat event: %v", err)
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-time.After(time.Second * 2):
+					// Continue after a short delay
+				}
+				continue
+			}
+
+			offset := commits.track(msg)
+			completed := make([]*pendingOffset, 0, 2)
+
+			mutex.Lock()
+			previous, exist := watched[hb.ID]
+			switch {
+			case hb.Done:
+				// Only forget the task, if no other worker has started processing it in the meantime
+				if exist && !previous.hb.Started.After(hb.Started) {
+					delete(watched, hb.ID)
+					completed = append(completed, previous.offset)
+				}
+				completed = append(completed, offset)
+
+			case hb.Submit != nil:
+				watched[hb.ID] = &watchedTask{
+					hb:     hb,
+					submit: hb.Submit,
+					seen:   time.Now(),
+					offset: offset,
+				}
+				if exist {
+					completed = append(completed, previous.offset)
+				}
+
+			default:
+				if exist && previous.hb.Started.Equal(hb.Started) {
+					previous.hb = hb
+					previous.seen = time.Now()
+				}
+				completed = append(completed, offset)
+			}
+			mutex.Unlock()
+
+			for _, offset := range completed {
+				w.commit(ctx, reader, commits, offset)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(w.options.Heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			var lost []*watchedTask
+
+			mutex.Lock()
+			for id, task := range watched {
+				// Heartbeats are judged by the time they were received, which is also
+				// correct for heartbeats read again after a rebalance
+				if time.Since(task.seen) > w.options.LostAfter {
+					lost = append(lost, task)
+					delete(watched, id)
+				}
+			}
+			mutex.Unlock()
+
+			for _, task := range lost {
+				w.processLost(ctx, task)
+				w.commit(ctx, reader, commits, task.offset)
+			}
+		}
+	}
+}
+
+// processLost reports the task as lost or retries it, unless it has already been processed.
+// Tasks that finished processing are already forgotten by their done heartbeat, which follows
+// all status updates of the task; The processed store only covers workers stopped in between.
+func (w *Worker) processLost(ctx context.Context, task *watchedTask) {
+	hb := task.hb
+
+	if ev := w.processed(ctx, task.submit); ev != nil {
+		return
+	}
+
+	w.logger.Warn("Task '%s' lost; No heartbeat from worker '%s' since '%v'", hb.ID, hb.Worker, hb.Time)
+
+	p := &Pipeline{
+		logger:  w.logger.Named("asynk/pipeline"),
+		session: w.session,
+		codecs:  w.codecs,
+		codec:   w.codec,
+		submit:  task.submit,
+	}
+
+	cause := fmt.Errorf("%w: no heartbeat from worker '%s' since '%s'", ErrLost, hb.Worker, hb.Time.Format(time.RFC3339))
+	if err := w.processFailure(ctx, p, cause); err != nil {
+		w.logger.Warn("Error processing lost task: %v", err)
+	}
+}
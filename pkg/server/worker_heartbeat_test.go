@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func TestWatchdogRetriesLostTask(t *testing.T) {
+	broker := memory.NewBroker(options.WithDefaultPartitions(4))
+
+	// Tasks are committed before processing, so only the watchdog is able to recover them
+	opts := []options.RouteOption{
+		options.WithDeliveryMode(options.DeliveryAtMostOnce),
+		options.WithHeartbeat(time.Millisecond*100, time.Millisecond*500),
+		options.WithRetryLost(),
+		options.WithMaxRetries(1),
+		options.WithBackoff(time.Millisecond*100, time.Millisecond*100, 1),
+	}
+
+	started := make(chan struct{})
+	block := make(chan struct{})
+	defer close(block)
+
+	first := newFaultyTransport(t, broker)
+	startWorker(t, first, func(ctx context.Context, p *Pipeline) error {
+		close(started)
+		<-block
+		return nil
+	}, opts...)
+
+	submit(t, broker, &event.SubmitEvent{ID: "a"})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second * 10):
+		t.Fatal("task has not been started")
+	}
+
+	// Several heartbeats are written, before the worker is killed
+	time.Sleep(time.Millisecond * 300)
+	first.kill()
+
+	retried := make(chan struct{}, 1)
+	startWorker(t, newFaultyTransport(t, broker), func(ctx context.Context, p *Pipeline) error {
+		if p.Submit().Metadata[event.MetadataRetryCount] == "1" {
+			select {
+			case retried <- struct{}{}:
+			default:
+			}
+		}
+		return nil
+	}, opts...)
+
+	select {
+	case <-retried:
+	case <-time.After(time.Second * 10):
+		t.Fatal("lost task has not been retried")
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+// lane reads the submit topic of a single priority.
+type lane struct {
+	priority event.Priority
+	reader   transport.Reader
+	commits  *commitTracker
+	fetched  chan *fetchedEvent
+
+	weight  int
+	current int
+}
+
+type fetchedEvent struct {
+	msg transport.Message
+	ev  *event.SubmitEvent
+	err error
+}
+
+func (w *Worker) newLanes() []*lane {
+	weights := map[event.Priority]int{
+		event.PriorityHigh:   w.options.Weights.High,
+		event.PriorityNormal: w.options.Weights.Normal,
+		event.PriorityLow:    w.options.Weights.Low,
+	}
+
+	lanes := make([]*lane, 0, len(event.Priorities))
+	for _, priority := range event.Priorities {
+		lanes = append(lanes, &lane{
+			priority: priority,
+			reader:   w.session.GetReader(priority.Topic()),
+			commits:  newCommitTracker(),
+			fetched:  make(chan *fetchedEvent, 1),
+			weight:   max(1, weights[priority]),
+		})
+	}
+
+	return lanes
+}
+
+// processLane keeps fetching events from the lane and wakes up the worker for every fetched event.
+// After a failed fetch, the lane waits a short delay, so that a persistent error does not keep it spinning.
+func (w *Worker) processLane(ctx context.Context, l *lane, wake chan struct{}) {
+	defer w.running.Done()
+
+	for {
+		ev := &event.SubmitEvent{}
+		msg, err := l.reader.FetchEvent(ctx, ev)
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case l.fetched <- &fetchedEvent{msg: msg, ev: ev, err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+
+		var derr *transport.DecodeError
+		if err != nil && !errors.As(err, &derr) {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-time.After(time.Second * 2):
+				// Continue after a short delay
+			}
+		}
+	}
+}
+
+// nextLane waits until at least one lane has a fetched event and selects the lane to process next.
+func (w *Worker) nextLane(ctx context.Context, lanes []*lane, wake chan struct{}) (*lane, bool) {
+	for {
+		// Only the worker receives from the lanes, so a buffered event can always be received
+		var ready []*lane
+		for _, l := range lanes {
+			if len(l.fetched) > 0 {
+				ready = append(ready, l)
+			}
+		}
+
+		if len(ready) > 0 {
+			if w.options.Priority == options.PriorityStrict {
+				return ready[0], true
+			}
+			return weighted(ready), true
+		}
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// weighted selects one of the lanes using a smooth weighted round-robin.
+func weighted(lanes []*lane) *lane {
+	var selected *lane
+	total := 0
+
+	for _, l := range lanes {
+		l.current += l.weight
+		total += l.weight
+
+		if selected == nil || l.current > selected.current {
+			selected = l
+		}
+	}
+
+	selected.current -= total
+	return selected
+}
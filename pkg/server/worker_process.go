@@ -2,82 +2,282 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/mwantia/asynk/internal/kafka"
 	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
 )
 
 func (w *Worker) processPipeline(ctx context.Context, p *Pipeline, h Handler) error {
-	process, cancel := context.WithTimeout(ctx, time.Minute*5)
-	defer cancel()
+	controlled, cancelCause := context.WithCancelCause(ctx)
+	defer cancelCause(nil)
 
-	w.logger.Debug("Processing pipeline for task '%s'", p.submit.ID)
+	if ctrl := w.register(p.submit.ID, cancelCause); ctrl != nil {
+		w.logger.Info("Skipping task '%s' due to control action '%s'", p.submit.ID, ctrl.Action)
+		return w.controlStatus(ctx, p, ctrl)
+	}
+	defer w.unregister(p.submit.ID)
 
-	if err := h.ProcessPipeline(process, p); err != nil {
-		w.logger.Error("Failed to process task '%s': %v", p.submit.ID, err)
-		errs := p.Status(ctx, &event.StatusEvent{
-			Status: event.StatusFailed,
-			Metadata: event.Metadata{
-				event.MetadataLastError:   err.Error(),
-				event.MetadataLastAttempt: time.Now().Format(time.RFC3339),
-			},
+	if ev := w.processed(ctx, p.submit); ev != nil {
+		w.logger.Info("Skipping task '%s' that has already been processed with status '%s'", p.submit.ID, ev.Status)
+		return p.Status(ctx, &event.StatusEvent{
+			Status:   ev.Status,
+			Metadata: ev.Metadata,
 		})
+	}
 
-		if errs != nil {
-			return fmt.Errorf("failed to update status after error: %v (original error: %w)", errs, err)
+	now := time.Now()
+	if !p.submit.IsDue(now) {
+		return w.handOff(ctx, p, func(ctx context.Context) error {
+			return w.delay(ctx, p.submit)
+		})
+	}
+	if p.submit.IsExpired(now) {
+		// Expired tasks never started, so their deadline is measured from the time they have been submitted
+		terr := &TimeoutError{
+			Err: Permanent(ErrDeadlineExceeded),
 		}
-		return fmt.Errorf("failed to process submit event: %w", err)
+		if !p.submit.Time.IsZero() {
+			terr.Timeout = p.submit.Deadline.Sub(p.submit.Time)
+			terr.Elapsed = now.Sub(p.submit.Time)
+		}
+		return w.processFailure(ctx, p, terr)
+	}
+
+	if isControlCause(context.Cause(controlled)) {
+		w.logger.Info("Skipping task '%s' due to control action received before processing", p.submit.ID)
+		return w.controlStatus(ctx, p, w.control(p.submit.ID))
+	}
+
+	deadline, cause := w.deadline(p.submit, now)
+
+	process, cancel := context.WithDeadlineCause(controlled, deadline, cause)
+	defer cancel()
+
+	w.logger.Debug("Processing pipeline for task '%s' until '%v'", p.submit.ID, deadline)
+
+	stop := w.heartbeat(ctx, p)
+	defer stop()
+
+	if err := w.runHandler(process, p, h); err != nil {
+		if isControlCause(context.Cause(controlled)) {
+			w.logger.Info("Task '%s' stopped by control action: %v", p.submit.ID, context.Cause(controlled))
+			return w.controlStatus(ctx, p, w.control(p.submit.ID))
+		}
+
+		if errors.Is(context.Cause(controlled), ErrShutdown) {
+			return w.cutOff(ctx, p)
+		}
+
+		if controlled.Err() == nil && process.Err() != nil {
+			err = &TimeoutError{
+				Timeout: deadline.Sub(now),
+				Elapsed: time.Since(now),
+				Err:     context.Cause(process),
+			}
+		}
+
+		return w.processFailure(ctx, p, err)
 	}
 
 	return nil
 }
 
-func (w *Worker) processNextEvent(ctx context.Context, reader *kafka.Reader, h Handler) error {
-	timeout, cancel := context.WithTimeout(ctx, time.Minute*1)
-	defer cancel()
+// processFailure either retries the task or reports it as failed and moves it to the dead-letter topic.
+func (w *Worker) processFailure(ctx context.Context, p *Pipeline, err error) error {
+	if w.shouldRetry(p.submit, err) {
+		return w.scheduleRetry(ctx, p, err)
+	}
 
-	ev := &event.SubmitEvent{}
-	if err := reader.ReadEvent(ctx, ev); err != nil {
-		if timeout.Err() != nil {
-			w.logger.Debug("Timeout reading next event")
-			return nil
+	w.logger.Error("Failed to process task '%s': %v", p.submit.ID, err)
+
+	count, _ := w.retryState(p.submit)
+	status := &event.StatusEvent{
+		Status: event.StatusFailed,
+		Metadata: event.Metadata{
+			event.MetadataRetryCount:  strconv.Itoa(count),
+			event.MetadataLastError:   err.Error(),
+			event.MetadataLastAttempt: time.Now().Format(time.RFC3339),
+		},
+	}
+
+	var terr *TimeoutError
+	if errors.As(err, &terr) {
+		status.Status = event.StatusTimeout
+		status.Metadata[event.MetadataElapsed] = terr.Elapsed.String()
+		if terr.Timeout > 0 {
+			status.Metadata[event.MetadataTimeout] = terr.Timeout.String()
+		}
+	}
+	if errors.Is(err, ErrLost) {
+		status.Status = event.StatusLost
+	}
+
+	errs := p.Status(ctx, status)
+
+	if errs != nil {
+		return fmt.Errorf("failed to update status after error: %v (original error: %w)", errs, err)
+	}
+
+	if errs := w.deadLetter(ctx, p.submit, err); errs != nil {
+		return fmt.Errorf("failed to dead-letter task: %v (original error: %w)", errs, err)
+	}
+	return fmt.Errorf("failed to process submit event: %w", err)
+}
+
+// timeout returns the timeout of the task, which overrides the timeout of the route.
+func (w *Worker) timeout(submit *event.SubmitEvent) time.Duration {
+	if submit.Timeout > 0 {
+		return submit.Timeout
+	}
+	return w.options.Timeout
+}
+
+// deadline returns the time at which processing the task is stopped, limited by the absolute deadline of the task.
+func (w *Worker) deadline(submit *event.SubmitEvent, now time.Time) (time.Time, error) {
+	deadline := now.Add(w.timeout(submit))
+	if !submit.Deadline.IsZero() && submit.Deadline.Before(deadline) {
+		return submit.Deadline, Permanent(ErrDeadlineExceeded)
+	}
+
+	return deadline, ErrTimeout
+}
+
+// runHandler calls the handler and converts any panic into an error, so that only the task fails instead of the worker.
+func (w *Worker) runHandler(ctx context.Context, p *Pipeline, h Handler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logger.Error("Handler panic for task '%s': %v\n%s", p.submit.ID, r, debug.Stack())
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+	}()
+
+	return h.ProcessPipeline(ctx, p)
+}
+
+// processNextEvent selects the next event, until fetching is stopped, and processes it in its own pipeline.
+func (w *Worker) processNextEvent(fetching, ctx context.Context, lanes []*lane, wake chan struct{}, h Handler) error {
+	// Wait for a free slot before selecting the next event
+	select {
+	case w.slots <- struct{}{}:
+	case <-fetching.Done():
+		return nil
+	}
+
+	l, ok := w.nextLane(fetching, lanes, wake)
+	if !ok {
+		<-w.slots
+		return nil
+	}
+
+	fetched := <-l.fetched
+	msg, ev := fetched.msg, fetched.ev
+
+	if err := fetched.err; err != nil {
+		defer func() { <-w.slots }()
+
+		var derr *transport.DecodeError
+		if errors.As(err, &derr) {
+			offset := l.commits.track(msg)
+			defer w.commit(ctx, l.reader, l.commits, offset)
+
+			return w.deadLetterMessage(ctx, derr)
 		}
-		return fmt.Errorf("failed to read kafka message: %w", err)
+
+		return fmt.Errorf("failed to fetch submit event: %w", err)
 	}
 
-	return w.processPipeline(ctx, &Pipeline{
+	offset := l.commits.track(msg)
+	ack := sync.OnceFunc(func() {
+		w.commit(ctx, l.reader, l.commits, offset)
+	})
+
+	if w.options.Delivery == options.DeliveryAtMostOnce {
+		ack()
+	}
+
+	p := &Pipeline{
 		logger:  w.logger.Named("asynk/pipeline"),
 		session: w.session,
+		codecs:  w.codecs,
+		codec:   w.codec,
 		submit:  ev,
-	}, h)
+		ack:     ack,
+	}
+
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+		defer func() { <-w.slots }()
+
+		if err := w.processPipeline(ctx, p, h); err != nil {
+			w.logger.Warn("Error processing event: %v", err)
+		}
+
+		w.remember(context.WithoutCancel(ctx), p)
+		p.release()
+	}()
+
+	return nil
 }
 
+// Process fetches and processes tasks until the context is cancelled or the worker is shut down.
 func (w *Worker) Process(ctx context.Context, handler Handler) error {
-	processing, cancel := context.WithCancel(ctx)
+	processing, cancel := context.WithCancelCause(ctx)
+	fetching, drain := context.WithCancel(processing)
+
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		drain()
+		cancel(ErrShutdown)
+		return nil
+	}
 	w.cancel = cancel
+	w.drain = drain
+	w.mutex.Unlock()
 
-	w.logger.Info("Starting worker processing...")
+	w.logger.Info("Starting worker processing with concurrency '%d' and '%s' priority...", cap(w.slots), w.options.Priority)
 
 	if err := w.initializeTopic(ctx); err != nil {
 		return fmt.Errorf("failed to initialize topics: %w", err)
 	}
 
-	reader := w.session.GetReader("events.submit")
+	lanes := w.newLanes()
+	wake := make(chan struct{}, 1)
+
+	for _, l := range lanes {
+		w.running.Add(1)
+		go w.processLane(fetching, l, wake)
+	}
+
+	w.running.Add(1)
+	go w.processControl(processing)
+
+	w.running.Add(1)
+	go w.processDelayed(fetching)
+
+	w.running.Add(1)
+	go w.processWatchdog(processing)
 
 	w.running.Add(1)
 	defer w.running.Done()
 
 	for {
 		select {
-		case <-ctx.Done():
+		case <-fetching.Done():
 			w.logger.Info("Worker processing stopped")
 			return nil
 
 		default:
-			if err := w.processNextEvent(processing, reader, handler); err != nil {
-				fmt.Printf("Error processing event: %v\n", err)
+			if err := w.processNextEvent(fetching, processing, lanes, wake, handler); err != nil {
+				w.logger.Warn("Error processing event: %v", err)
 				continue
 			}
 		}
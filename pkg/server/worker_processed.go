@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+// processed returns the terminal status of the task, if it has already been processed.
+// Tasks submitted again after reaching their terminal status are processed once more.
+func (w *Worker) processed(ctx context.Context, submit *event.SubmitEvent) *event.StatusEvent {
+	if w.options.Processed == nil {
+		return nil
+	}
+
+	ev, err := w.options.Processed.Get(ctx, submit.ID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			w.logger.Warn("Failed to check processed store for task '%s': %v", submit.ID, err)
+		}
+		return nil
+	}
+
+	if ev.Time.Before(submit.Time) {
+		return nil
+	}
+
+	return ev
+}
+
+// remember stores the terminal status emitted by the pipeline in the processed store.
+func (w *Worker) remember(ctx context.Context, p *Pipeline) {
+	if w.options.Processed == nil || p.terminal == nil {
+		return
+	}
+
+	if err := w.options.Processed.Put(ctx, p.terminal, w.options.ProcessedTTL); err != nil {
+		w.logger.Warn("Failed to remember processed task '%s': %v", p.submit.ID, err)
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+// retryState returns the current retry count and limit of the submit event.
+// The limit stored in the event metadata takes precedence over the route policy.
+func (w *Worker) retryState(ev *event.SubmitEvent) (int, int) {
+	count, limit := 0, w.options.Retry.MaxRetries
+
+	if value, exist := ev.Metadata[event.MetadataRetryCount]; exist {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			count = parsed
+		}
+	}
+
+	if value, exist := ev.Metadata[event.MetadataRetryLimit]; exist {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			limit = parsed
+		}
+	}
+
+	return count, limit
+}
+
+func (w *Worker) shouldRetry(ev *event.SubmitEvent, err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	if errors.Is(err, ErrLost) && !w.options.RetryLost {
+		return false
+	}
+
+	if !w.options.Retry.IsRetryable(err) {
+		return false
+	}
+
+	count, limit := w.retryState(ev)
+	return count < limit
+}
+
+// scheduleRetry reports the retry status and re-enqueues the submit event after the backoff.
+func (w *Worker) scheduleRetry(ctx context.Context, p *Pipeline, cause error) error {
+	count, limit := w.retryState(p.submit)
+	count++
+
+	now := time.Now()
+	backoff := w.options.Retry.Backoff(count)
+
+	retry := *p.submit
+	retry.Metadata = event.Metadata{}
+	for key, value := range p.submit.Metadata {
+		retry.Metadata[key] = value
+	}
+
+	retry.Metadata[event.MetadataRetryCount] = strconv.Itoa(count)
+	retry.Metadata[event.MetadataRetryLimit] = strconv.Itoa(limit)
+	retry.Metadata[event.MetadataLastError] = cause.Error()
+	retry.Metadata[event.MetadataLastAttempt] = now.Format(time.RFC3339)
+
+	retry.Attempts = append(append([]event.Attempt{}, p.submit.Attempts...), event.Attempt{
+		Retry: count - 1,
+		Time:  now,
+		Error: cause.Error(),
+	})
+
+	if err := p.Status(ctx, &event.StatusEvent{
+		Status: event.StatusRetry,
+		Metadata: event.Metadata{
+			event.MetadataRetryCount:  strconv.Itoa(count),
+			event.MetadataRetryLimit:  strconv.Itoa(limit),
+			event.MetadataLastError:   cause.Error(),
+			event.MetadataLastAttempt: now.Format(time.RFC3339),
+			event.MetadataNextAttempt: now.Add(backoff).Format(time.RFC3339),
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to update retry status: %w", err)
+	}
+
+	w.logger.Warn("Retrying task '%s' in '%v' (%d/%d)", retry.ID, backoff, count, limit)
+
+	retry.NotBefore = now.Add(backoff)
+	if err := w.handOff(ctx, p, func(ctx context.Context) error {
+		return w.delay(ctx, &retry)
+	}); err != nil {
+		return fmt.Errorf("failed to re-enqueue task: %w", err)
+	}
+
+	return nil
+}
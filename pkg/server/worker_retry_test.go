@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+func TestRetrySurvivesKilledWorker(t *testing.T) {
+	broker := memory.NewBroker()
+
+	failed := make(chan struct{}, 1)
+
+	first := newFaultyTransport(t, broker)
+	startWorker(t, first, func(ctx context.Context, p *Pipeline) error {
+		failed <- struct{}{}
+		return errors.New("temporary failure")
+	}, options.WithMaxRetries(1), options.WithBackoff(time.Second*2, time.Second*2, 1), options.WithJitter(0))
+
+	submit(t, broker, &event.SubmitEvent{ID: "a"})
+
+	select {
+	case <-failed:
+	case <-time.After(time.Second * 10):
+		t.Fatal("task has not been processed")
+	}
+
+	// The offset of the task is only committed once its retry has been written
+	waitFor(t, func() bool {
+		return first.commits(event.PriorityNormal.Topic()) == 1
+	}, "offset of the failed task has not been committed")
+
+	// Killed during the backoff, while the retry is still waiting
+	first.kill()
+
+	rec := newRecorder()
+	startWorker(t, newFaultyTransport(t, broker), rec.handle)
+
+	rec.await(t, "a")
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+	"github.com/mwantia/asynk/pkg/transport/memory"
+)
+
+var errInjected = errors.New("injected failure")
+
+// faultyTransport wraps a memory transport, which can be killed and fail writes to single topics.
+// A killed transport neither writes nor commits anything, as if the process has been killed.
+type faultyTransport struct {
+	*memory.Client
+
+	killed atomic.Bool
+
+	mutex     sync.Mutex
+	failures  map[string]int
+	committed map[string]int64
+}
+
+type faultySession struct {
+	transport.Session
+	transport *faultyTransport
+}
+
+type faultyWriter struct {
+	transport.Writer
+	transport *faultyTransport
+	topic     string
+}
+
+type faultyReader struct {
+	transport.Reader
+	transport *faultyTransport
+	topic     string
+}
+
+func newFaultyTransport(t *testing.T, broker *memory.Broker) *faultyTransport {
+	t.Helper()
+
+	c, err := memory.NewMemory(broker, options.WithLogLevel("ERROR"), options.WithGroupID("workers"))
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+
+	return &faultyTransport{
+		Client:    c,
+		failures:  make(map[string]int),
+		committed: make(map[string]int64),
+	}
+}
+
+func (f *faultyTransport) Session(suffix string) (transport.Session, error) {
+	s, err := f.Client.Session(suffix)
+	if err != nil {
+		return nil, err
+	}
+	return &faultySession{Session: s, transport: f}, nil
+}
+
+// fail lets the next writes to the topic fail.
+func (f *faultyTransport) fail(topic string, writes int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.failures[topic] = writes
+}
+
+// kill stops all writes and commits and lets the readers leave their group.
+func (f *faultyTransport) kill() {
+	f.killed.Store(true)
+	f.Client.Cleanup()
+}
+
+// commits returns the number of messages committed for the topic.
+func (f *faultyTransport) commits(topic string) int64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.committed[topic]
+}
+
+func (s *faultySession) GetWriter(topic string) transport.Writer {
+	return &faultyWriter{Writer: s.Session.GetWriter(topic), transport: s.transport, topic: topic}
+}
+
+func (s *faultySession) GetReader(topic string, opts ...options.ReaderOption) transport.Reader {
+	return &faultyReader{Reader: s.Session.GetReader(topic, opts...), transport: s.transport, topic: topic}
+}
+
+func (w *faultyWriter) WriteEvent(ctx context.Context, ev event.Event) error {
+	if w.transport.killed.Load() {
+		return errInjected
+	}
+
+	w.transport.mutex.Lock()
+	failing := w.transport.failures[w.topic] > 0
+	if failing {
+		w.transport.failures[w.topic]--
+	}
+	w.transport.mutex.Unlock()
+
+	if failing {
+		return errInjected
+	}
+	return w.Writer.WriteEvent(ctx, ev)
+}
+
+func (r *faultyReader) Commit(ctx context.Context, msgs ...transport.Message) error {
+	if r.transport.killed.Load() {
+		return errInjected
+	}
+	if err := r.Reader.Commit(ctx, msgs...); err != nil {
+		return err
+	}
+
+	r.transport.mutex.Lock()
+	defer r.transport.mutex.Unlock()
+
+	for _, msg := range msgs {
+		r.transport.committed[r.topic] = max(r.transport.committed[r.topic], msg.Offset+1)
+	}
+	return nil
+}
+
+// startWorker runs a worker on the transport until the test has finished.
+func startWorker(t *testing.T, tr *faultyTransport, handler HandlerFunc, opts ...options.RouteOption) {
+	t.Helper()
+
+	s, err := NewServerWithTransport(tr, options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+
+	session, err := tr.Session("test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	route := options.DefaultRouteOptions()
+	for _, opt := range opts {
+		if err := opt(&route); err != nil {
+			t.Fatalf("failed to apply route option: %v", err)
+		}
+	}
+
+	w, err := NewWorker(s, session, route)
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Process(ctx, handler)
+	}()
+
+	t.Cleanup(func() {
+		tr.kill()
+		cancel()
+		<-done
+	})
+}
+
+func submit(t *testing.T, broker *memory.Broker, evs ...*event.SubmitEvent) {
+	t.Helper()
+
+	c, err := memory.NewMemory(broker, options.WithLogLevel("ERROR"))
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+
+	s, err := c.Session("test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	for _, ev := range evs {
+		if ev.Time.IsZero() {
+			ev.Time = time.Now()
+		}
+		if err := s.GetWriter(ev.Priority.Topic()).WriteEvent(context.Background(), ev); err != nil {
+			t.Fatalf("failed to submit task '%s': %v", ev.ID, err)
+		}
+	}
+}
+
+// recorder counts the calls of the handler per task.
+type recorder struct {
+	mutex sync.Mutex
+	calls map[string]int
+	next  chan string
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		calls: make(map[string]int),
+		next:  make(chan string, 16),
+	}
+}
+
+func (r *recorder) handle(ctx context.Context, p *Pipeline) error {
+	r.mutex.Lock()
+	r.calls[p.Submit().ID]++
+	r.mutex.Unlock()
+
+	r.next <- p.Submit().ID
+	return nil
+}
+
+func (r *recorder) count(id string) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.calls[id]
+}
+
+// await waits until the handler has been called for the task.
+func (r *recorder) await(t *testing.T, id string) {
+	t.Helper()
+
+	timeout := time.After(time.Second * 10)
+	for {
+		select {
+		case next := <-r.next:
+			if next == id {
+				return
+			}
+
+		case <-timeout:
+			t.Fatalf("task '%s' has not been processed", id)
+		}
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool, message string) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second * 10)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+}
+
+func TestWorkerRedeliversTaskOfKilledWorker(t *testing.T) {
+	broker := memory.NewBroker()
+
+	started := make(chan struct{})
+	block := make(chan struct{})
+	defer close(block)
+
+	first := newFaultyTransport(t, broker)
+	startWorker(t, first, func(ctx context.Context, p *Pipeline) error {
+		close(started)
+		<-block
+		return nil
+	})
+
+	submit(t, broker, &event.SubmitEvent{ID: "a"})
+
+	select {
+	case <-started:
+	case <-time.After(time.Second * 10):
+		t.Fatal("task has not been started")
+	}
+
+	// Killed while the task is still running, so its offset has never been committed
+	first.kill()
+
+	rec := newRecorder()
+	startWorker(t, newFaultyTransport(t, broker), rec.handle)
+
+	rec.await(t, "a")
+}
+
+func TestWorkerCommitsTaskBehindFailedHandOff(t *testing.T) {
+	broker := memory.NewBroker()
+
+	first := newFaultyTransport(t, broker)
+	first.fail("events.delayed", 1)
+
+	rec := newRecorder()
+	startWorker(t, first, rec.handle, options.WithConcurrency(2))
+
+	// The delayed task is handed off only after the failed write has been retried
+	submit(t, broker,
+		&event.SubmitEvent{ID: "delayed", NotBefore: time.Now().Add(time.Hour)},
+		&event.SubmitEvent{ID: "b"},
+	)
+	rec.await(t, "b")
+
+	waitFor(t, func() bool {
+		return first.commits(event.PriorityNormal.Topic()) == 2
+	}, "offsets behind the delayed task have not been committed")
+
+	first.kill()
+
+	second := newRecorder()
+	startWorker(t, newFaultyTransport(t, broker), second.handle)
+
+	submit(t, broker, &event.SubmitEvent{ID: "c"})
+	second.await(t, "c")
+
+	if n := second.count("b"); n != 0 {
+		t.Fatalf("expected task 'b' to not be processed again, got '%d' calls", n)
+	}
+	if n := second.count("delayed"); n != 0 {
+		t.Fatalf("expected delayed task to not be processed yet, got '%d' calls", n)
+	}
+}
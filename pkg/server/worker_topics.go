@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/mwantia/asynk/pkg/event"
 	"github.com/mwantia/asynk/pkg/options"
 )
 
 func (w *Worker) initializeTopic(ctx context.Context) error {
 	w.logger.Info("Initializing topics for worker")
 
-	if err := w.session.CreateTopic(ctx, "events.submit",
-		options.WithRetentionTime(time.Hour*24),
-	); err != nil {
-		return fmt.Errorf("failed to create topic '%s': %w", "events.submit", err)
+	for _, priority := range event.Priorities {
+		if err := w.session.CreateTopic(ctx, priority.Topic(),
+			options.WithRetentionTime(time.Hour*24),
+		); err != nil {
+			return fmt.Errorf("failed to create topic '%s': %w", priority.Topic(), err)
+		}
 	}
 
 	if err := w.session.CreateTopic(ctx, "events.status",
@@ -23,6 +26,46 @@ func (w *Worker) initializeTopic(ctx context.Context) error {
 		return fmt.Errorf("failed to create topic '%s': %w", "events.status", err)
 	}
 
+	if err := w.session.CreateTopic(ctx, "events.delayed",
+		options.WithRetentionTime(time.Hour*24*30),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.delayed", err)
+	}
+
+	for _, tier := range delayTiers {
+		if err := w.session.CreateTopic(ctx, tier.topic,
+			options.WithRetentionTime(time.Hour*24*7),
+		); err != nil {
+			return fmt.Errorf("failed to create topic '%s': %w", tier.topic, err)
+		}
+	}
+
+	if err := w.session.CreateTopic(ctx, "events.control",
+		options.WithNumPartitions(1),
+		options.WithRetentionTime(controlRetention),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.control", err)
+	}
+
+	if err := w.session.CreateTopic(ctx, "events.dead",
+		options.WithRetentionTime(time.Hour*24*7),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.dead", err)
+	}
+
+	if err := w.session.CreateTopic(ctx, "events.heartbeat",
+		options.WithRetentionTime(time.Hour*2),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.heartbeat", err)
+	}
+
+	if err := w.session.CreateTopic(ctx, "events.unique",
+		options.WithNumPartitions(1),
+		options.WithRetentionTime(options.MaxUniqueTTL),
+	); err != nil {
+		return fmt.Errorf("failed to create topic '%s': %w", "events.unique", err)
+	}
+
 	w.logger.Info("Topics initialized successfully")
 	return nil
 }
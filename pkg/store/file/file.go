@@ -0,0 +1,205 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+var _ store.Store = (*Store)(nil)
+
+const (
+	// The file is compacted once it contains more lines than this ratio of the number of tasks
+	compactRatio    = 4
+	compactMinLines = 1024
+)
+
+// Store is an embedded store that appends every task state to a local file.
+// The file is replayed when opened and compacted whenever it has grown too large and once the store is closed.
+type Store struct {
+	mutex sync.RWMutex
+	path  string
+	file  *os.File
+	lines int
+	tasks map[string]*store.Task
+}
+
+func NewStore(path string) (*Store, error) {
+	s := &Store{
+		path:  path,
+		tasks: make(map[string]*store.Task),
+	}
+
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("failed to load store '%s': %w", path, err)
+	}
+
+	if err := s.open(); err != nil {
+		return nil, fmt.Errorf("failed to open store '%s': %w", path, err)
+	}
+
+	return s, nil
+}
+
+func (s *Store) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+	return nil
+}
+
+func (s *Store) Apply(ctx context.Context, ev *event.StatusEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return errors.New("store has already been closed")
+	}
+
+	task, exist := s.tasks[ev.ID]
+	if !exist {
+		task = store.NewTask(ev.ID)
+		s.tasks[ev.ID] = task
+	}
+
+	task.Apply(ev)
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync task: %w", err)
+	}
+
+	s.lines++
+	if s.lines > max(compactMinLines, compactRatio*len(s.tasks)) {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("failed to compact store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rotate compacts the file while the store remains open.
+func (s *Store) rotate() error {
+	errs := errors.Join(s.file.Close(), s.compact())
+
+	if err := s.open(); err != nil {
+		s.file = nil
+		return errors.Join(errs, err)
+	}
+
+	return errs
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*store.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	task, exist := s.tasks[id]
+	if !exist {
+		return nil, store.ErrNotFound
+	}
+
+	return task.Clone(), nil
+}
+
+func (s *Store) List(ctx context.Context, filter store.Filter) ([]*store.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return store.Select(slices.Collect(maps.Values(s.tasks)), filter), nil
+}
+
+func (s *Store) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	errs := s.file.Close()
+	s.file = nil
+
+	return errors.Join(errs, s.compact())
+}
+
+// load replays the file, where the last written state of every task wins.
+func (s *Store) load() error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		task := &store.Task{}
+		if err := json.Unmarshal(scanner.Bytes(), task); err != nil {
+			// Skip partially written lines after a crash
+			continue
+		}
+
+		s.tasks[task.ID] = task
+		s.lines++
+	}
+
+	return scanner.Err()
+}
+
+// compact rewrites the file to only contain the latest state of every task.
+func (s *Store) compact() error {
+	temp := s.path + ".tmp"
+
+	file, err := os.Create(temp)
+	if err != nil {
+		return fmt.Errorf("failed to create compaction file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, task := range s.tasks {
+		data, err := json.Marshal(task)
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to marshal task: %w", err)
+		}
+
+		writer.Write(append(data, '\n'))
+	}
+
+	if err := errors.Join(writer.Flush(), file.Sync(), file.Close()); err != nil {
+		return fmt.Errorf("failed to write compaction file: %w", err)
+	}
+
+	if err := os.Rename(temp, s.path); err != nil {
+		return err
+	}
+
+	s.lines = len(s.tasks)
+	return nil
+}
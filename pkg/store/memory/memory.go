@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+var _ store.Store = (*Store)(nil)
+
+// Store keeps all task states in memory and loses them once the process exits.
+type Store struct {
+	mutex sync.RWMutex
+	tasks map[string]*store.Task
+}
+
+func NewStore() *Store {
+	return &Store{
+		tasks: make(map[string]*store.Task),
+	}
+}
+
+func (s *Store) Apply(ctx context.Context, ev *event.StatusEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, exist := s.tasks[ev.ID]
+	if !exist {
+		task = store.NewTask(ev.ID)
+		s.tasks[ev.ID] = task
+	}
+
+	task.Apply(ev)
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, id string) (*store.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	task, exist := s.tasks[id]
+	if !exist {
+		return nil, store.ErrNotFound
+	}
+
+	return task.Clone(), nil
+}
+
+func (s *Store) List(ctx context.Context, filter store.Filter) ([]*store.Task, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return store.Select(slices.Collect(maps.Values(s.tasks)), filter), nil
+}
+
+func (s *Store) Close() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/store"
+)
+
+var _ store.Processed = (*Processed)(nil)
+
+// Processed keeps the terminal status of finished tasks in memory until their ttl has passed.
+type Processed struct {
+	mutex   sync.Mutex
+	entries map[string]*processedEntry
+	purged  time.Time
+}
+
+type processedEntry struct {
+	ev      *event.StatusEvent
+	expires time.Time
+}
+
+func NewProcessed() *Processed {
+	return &Processed{
+		entries: make(map[string]*processedEntry),
+	}
+}
+
+func (p *Processed) Get(ctx context.Context, id string) (*event.StatusEvent, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	entry, exist := p.entries[id]
+	if !exist || time.Now().After(entry.expires) {
+		return nil, store.ErrNotFound
+	}
+
+	ev := *entry.ev
+	ev.Metadata = maps.Clone(entry.ev.Metadata)
+
+	return &ev, nil
+}
+
+func (p *Processed) Put(ctx context.Context, ev *event.StatusEvent, ttl time.Duration) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+
+	clone := *ev
+	clone.Metadata = maps.Clone(ev.Metadata)
+
+	p.entries[ev.ID] = &processedEntry{
+		ev:      &clone,
+		expires: now.Add(ttl),
+	}
+
+	// Remove expired entries from time to time
+	if now.Sub(p.purged) >= time.Minute {
+		p.purged = now
+		maps.DeleteFunc(p.entries, func(_ string, entry *processedEntry) bool {
+			return now.After(entry.expires)
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+// Processed remembers the terminal status of finished tasks, so that workers
+// can skip tasks delivered again instead of processing them twice.
+type Processed interface {
+	// Get returns the terminal status of the task or ErrNotFound.
+	Get(ctx context.Context, id string) (*event.StatusEvent, error)
+
+	// Put remembers the terminal status of the task for at least the given ttl.
+	Put(ctx context.Context, ev *event.StatusEvent, ttl time.Duration) error
+}
+
+// NewProcessed uses the task states of the store as processed store.
+// Tasks are remembered for as long as the store keeps them, the ttl is ignored.
+func NewProcessed(s Store) Processed {
+	return &processed{
+		store: s,
+	}
+}
+
+type processed struct {
+	store Store
+}
+
+func (p *processed) Get(ctx context.Context, id string) (*event.StatusEvent, error) {
+	task, err := p.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.Status.IsTerminal() {
+		return nil, ErrNotFound
+	}
+
+	return &event.StatusEvent{
+		ID:       task.ID,
+		Time:     task.CompletedAt,
+		Status:   task.Status,
+		Metadata: task.Metadata,
+	}, nil
+}
+
+func (p *processed) Put(ctx context.Context, ev *event.StatusEvent, ttl time.Duration) error {
+	return p.store.Apply(ctx, ev)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+var (
+	ErrNotFound = errors.New("task not found")
+)
+
+// Store keeps the current state of every task, folded from its status events.
+type Store interface {
+	Apply(ctx context.Context, ev *event.StatusEvent) error
+
+	Get(ctx context.Context, id string) (*Task, error)
+
+	List(ctx context.Context, filter Filter) ([]*Task, error)
+
+	Close() error
+}
+
+type Filter struct {
+	Status []event.Status `json:"status,omitempty"`
+	Since  time.Time      `json:"since,omitempty"`
+	Until  time.Time      `json:"until,omitempty"`
+	Limit  int            `json:"limit,omitempty"`
+}
+
+// Match reports whether the task matches all conditions of the filter.
+func (f Filter) Match(t *Task) bool {
+	if len(f.Status) > 0 && !slices.Contains(f.Status, t.Status) {
+		return false
+	}
+	if !f.Since.IsZero() && t.UpdatedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && t.UpdatedAt.After(f.Until) {
+		return false
+	}
+
+	return true
+}
+
+// Select returns copies of all matching tasks ordered by their creation time.
+func Select(tasks []*Task, filter Filter) []*Task {
+	result := make([]*Task, 0)
+	for _, t := range tasks {
+		if filter.Match(t) {
+			result = append(result, t.Clone())
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *Task) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+
+	return result
+}
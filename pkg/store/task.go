@@ -0,0 +1,78 @@
+package store
+
+import (
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+type Task struct {
+	ID          string         `json:"id"`
+	Status      event.Status   `json:"status"`
+	Attempts    int            `json:"attempts"`
+	LastError   string         `json:"last_error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	CompletedAt time.Time      `json:"completed_at,omitempty"`
+	Metadata    event.Metadata `json:"metadata,omitempty"`
+}
+
+func NewTask(id string) *Task {
+	return &Task{
+		ID:       id,
+		Metadata: event.Metadata{},
+	}
+}
+
+// Apply folds the status event into the current state of the task.
+// Events older than the last update only contribute to the creation time of the task.
+func (t *Task) Apply(ev *event.StatusEvent) {
+	if t.CreatedAt.IsZero() || ev.Time.Before(t.CreatedAt) {
+		t.CreatedAt = ev.Time
+	}
+
+	if ev.Time.Before(t.UpdatedAt) {
+		return
+	}
+
+	if t.Metadata == nil {
+		t.Metadata = event.Metadata{}
+	}
+	maps.Copy(t.Metadata, ev.Metadata)
+
+	t.Status = ev.Status
+	t.UpdatedAt = ev.Time
+
+	retries := 0
+	if value, exist := ev.Metadata[event.MetadataRetryCount]; exist {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			retries = parsed
+		}
+	}
+
+	switch ev.Status {
+	case event.StatusPending:
+		// Nothing has been attempted yet
+	case event.StatusRetry:
+		t.Attempts = max(t.Attempts, retries)
+	default:
+		t.Attempts = max(t.Attempts, retries+1)
+	}
+
+	if value, exist := ev.Metadata[event.MetadataLastError]; exist {
+		t.LastError = value
+	}
+
+	if ev.Status.IsTerminal() {
+		t.CompletedAt = ev.Time
+	}
+}
+
+func (t *Task) Clone() *Task {
+	clone := *t
+	clone.Metadata = maps.Clone(t.Metadata)
+
+	return &clone
+}
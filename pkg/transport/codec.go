@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"fmt"
+
+	"github.com/mwantia/asynk/pkg/codec"
+	"github.com/mwantia/asynk/pkg/event"
+)
+
+// HeaderContentType is the message header containing the content type of the encoded event.
+const HeaderContentType = "content-type"
+
+// Encode marshals the event with the codec registered for the content type.
+func Encode(codecs *codec.Registry, contentType string, ev event.Event) ([]byte, error) {
+	c, err := codecs.Get(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event as '%s': %w", contentType, err)
+	}
+
+	return data, nil
+}
+
+// ValidateCodec checks that events can be encoded and decoded with the codec of the content type,
+// which excludes codecs only supporting payloads, like protobuf or raw bytes.
+func ValidateCodec(codecs *codec.Registry, contentType string) error {
+	c, err := codecs.Get(contentType)
+	if err != nil {
+		return err
+	}
+
+	data, err := Encode(codecs, contentType, &event.SubmitEvent{ID: event.UUIDv7()})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Unmarshal(data, &event.SubmitEvent{}); err != nil {
+		return fmt.Errorf("failed to decode event as '%s': %w", contentType, err)
+	}
+
+	return nil
+}
+
+// Decode unmarshals the message with the codec of its content type.
+// Messages without a content type are treated as json.
+func Decode(codecs *codec.Registry, msg Message, ev event.Event) error {
+	contentType := msg.Headers[HeaderContentType]
+	if contentType == "" {
+		contentType = codec.ContentTypeJSON
+	}
+
+	c, err := codecs.Get(contentType)
+	if err != nil {
+		return err
+	}
+
+	return c.Unmarshal(msg.Value, ev)
+}
@@ -0,0 +1,23 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/mwantia/asynk/pkg/codec"
+)
+
+func TestValidateCodecRejectsPayloadCodecs(t *testing.T) {
+	codecs := codec.NewRegistry()
+
+	for _, contentType := range []string{codec.ContentTypeJSON, codec.ContentTypeMsgpack} {
+		if err := ValidateCodec(codecs, contentType); err != nil {
+			t.Fatalf("expected '%s' to encode events, got: %v", contentType, err)
+		}
+	}
+
+	for _, contentType := range []string{codec.ContentTypeProtobuf, codec.ContentTypeRaw, "application/unknown"} {
+		if err := ValidateCodec(codecs, contentType); err == nil {
+			t.Fatalf("expected '%s' to be rejected for events", contentType)
+		}
+	}
+}
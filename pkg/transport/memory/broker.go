@@ -0,0 +1,351 @@
+package memory
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+const (
+	DefaultNumPartitions = 1
+)
+
+// Broker is an in-process message broker shared between all memory clients.
+type Broker struct {
+	mutex  sync.Mutex
+	topics map[string]*topic
+	notify chan struct{}
+
+	// Number of partitions used for topics created without an explicit partition count
+	partitions int
+}
+
+// topic holds the retained messages of every partition. Offsets stay stable once expired messages are dropped,
+// as the offset of the first retained message of every partition is kept as its base.
+type topic struct {
+	name       string
+	partitions [][]transport.Message
+	bases      []int
+	groups     map[string]*group
+
+	// Messages older than the retention are dropped; Topics created implicitly retain all messages
+	retention time.Duration
+}
+
+type group struct {
+	id         string
+	committed  []int
+	positions  []int
+	members    []*Reader
+	assignment map[*Reader][]int
+}
+
+func NewBroker(opts ...options.BrokerOption) *Broker {
+	options := options.BrokerOptions{
+		NumPartitions: DefaultNumPartitions,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Broker{
+		topics:     make(map[string]*topic),
+		notify:     make(chan struct{}),
+		partitions: max(1, options.NumPartitions),
+	}
+}
+
+// createTopic creates the topic or grows an existing topic to the number of partitions,
+// as topics are also created implicitly by writers and readers. A positive retention replaces the retention
+// of an existing topic. It expects the broker mutex to be held by the caller.
+func (b *Broker) createTopic(name string, numPartitions int, retention time.Duration) *topic {
+	if numPartitions <= 0 {
+		numPartitions = b.partitions
+	}
+
+	if t, exist := b.topics[name]; exist {
+		if retention > 0 {
+			t.retention = retention
+		}
+		if numPartitions > len(t.partitions) {
+			t.grow(numPartitions)
+			b.broadcast()
+		}
+		return t
+	}
+
+	t := &topic{
+		name:       name,
+		partitions: make([][]transport.Message, numPartitions),
+		bases:      make([]int, numPartitions),
+		groups:     make(map[string]*group),
+		retention:  max(0, retention),
+	}
+
+	b.topics[name] = t
+	return t
+}
+
+func (b *Broker) publish(name string, msg transport.Message) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.createTopic(name, 0, 0)
+	t.expire(msg.Time)
+
+	partition := t.partition(msg.Key)
+
+	msg.Topic = name
+	msg.Partition = partition
+	msg.Offset = int64(t.end(partition))
+	t.partitions[partition] = append(t.partitions[partition], msg)
+
+	b.broadcast()
+}
+
+// broadcast wakes up all readers waiting for new messages.
+// It expects the broker mutex to be held by the caller.
+func (b *Broker) broadcast() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func (b *Broker) join(name string, r *Reader) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t := b.createTopic(name, 0, 0)
+
+	if r.groupID == "" {
+		r.offsets = make([]int, len(t.partitions))
+		if r.latest {
+			for partition := range t.partitions {
+				r.offsets[partition] = t.end(partition)
+			}
+		}
+		return
+	}
+
+	g, exist := t.groups[r.groupID]
+	if !exist {
+		g = &group{
+			id:         r.groupID,
+			committed:  make([]int, len(t.partitions)),
+			positions:  make([]int, len(t.partitions)),
+			assignment: make(map[*Reader][]int),
+		}
+		t.groups[r.groupID] = g
+	}
+
+	g.members = append(g.members, r)
+	g.rebalance(len(t.partitions))
+
+	b.broadcast()
+}
+
+func (b *Broker) leave(name string, r *Reader) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, exist := b.topics[name]
+	if !exist || r.groupID == "" {
+		return
+	}
+
+	g, exist := t.groups[r.groupID]
+	if !exist {
+		return
+	}
+
+	for i, member := range g.members {
+		if member == r {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+
+	g.rebalance(len(t.partitions))
+
+	b.broadcast()
+}
+
+// next returns the next message available for the reader.
+// It expects the broker mutex to be held by the caller.
+func (b *Broker) next(name string, r *Reader) (transport.Message, bool) {
+	t := b.topics[name]
+
+	if r.groupID == "" {
+		for len(r.offsets) < len(t.partitions) {
+			r.offsets = append(r.offsets, 0)
+		}
+
+		for i := range t.partitions {
+			partition := (r.cursor + i) % len(t.partitions)
+			if msg, ok := t.at(partition, &r.offsets[partition]); ok {
+				r.cursor = partition + 1
+				return msg, true
+			}
+		}
+
+		return transport.Message{}, false
+	}
+
+	g := t.groups[r.groupID]
+	assigned := g.assignment[r]
+
+	for i := range assigned {
+		partition := assigned[(r.cursor+i)%len(assigned)]
+		if msg, ok := t.at(partition, &g.positions[partition]); ok {
+			r.cursor = (r.cursor + i + 1) % len(assigned)
+			return msg, true
+		}
+	}
+
+	return transport.Message{}, false
+}
+
+// grow adds partitions to the topic and rebalances all groups, the same way partitions are added to a kafka topic.
+func (t *topic) grow(numPartitions int) {
+	for len(t.partitions) < numPartitions {
+		t.partitions = append(t.partitions, nil)
+		t.bases = append(t.bases, 0)
+	}
+
+	for _, g := range t.groups {
+		for len(g.committed) < numPartitions {
+			g.committed = append(g.committed, 0)
+			g.positions = append(g.positions, 0)
+		}
+		g.rebalance(numPartitions)
+	}
+}
+
+// end returns the offset of the next message written to the partition.
+func (t *topic) end(partition int) int {
+	return t.bases[partition] + len(t.partitions[partition])
+}
+
+// at returns the message at the offset and advances the offset. Offsets of expired messages
+// are moved to the first retained message, the same way kafka resets offsets out of range.
+func (t *topic) at(partition int, offset *int) (transport.Message, bool) {
+	*offset = max(*offset, t.bases[partition])
+	if *offset >= t.end(partition) {
+		return transport.Message{}, false
+	}
+
+	msg := t.partitions[partition][*offset-t.bases[partition]]
+	*offset++
+
+	return msg, true
+}
+
+// expire drops all messages which have exceeded the retention of the topic.
+func (t *topic) expire(now time.Time) {
+	if t.retention <= 0 {
+		return
+	}
+
+	for partition, messages := range t.partitions {
+		n := 0
+		for n < len(messages) && now.Sub(messages[n].Time) > t.retention {
+			// Release the message, as the backing array is only reallocated by later writes
+			messages[n] = transport.Message{}
+			n++
+		}
+
+		t.partitions[partition] = messages[n:]
+		t.bases[partition] += n
+	}
+}
+
+func (t *topic) partition(key string) int {
+	if len(t.partitions) == 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(len(t.partitions)))
+}
+
+// commit stores the offsets of the messages as processed for the reader's group.
+func (b *Broker) commit(name string, r *Reader, msgs ...transport.Message) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, exist := b.topics[name]
+	if !exist || r.groupID == "" {
+		return
+	}
+
+	g, exist := t.groups[r.groupID]
+	if !exist {
+		return
+	}
+
+	for _, msg := range msgs {
+		if msg.Partition < len(g.committed) && int(msg.Offset)+1 > g.committed[msg.Partition] {
+			g.committed[msg.Partition] = int(msg.Offset) + 1
+		}
+	}
+}
+
+// rebalance assigns all partitions to the group members in a round-robin fashion.
+// Any fetched but uncommitted messages are delivered again after the rebalance.
+func (g *group) rebalance(numPartitions int) {
+	copy(g.positions, g.committed)
+
+	g.assignment = make(map[*Reader][]int)
+	if len(g.members) == 0 {
+		return
+	}
+
+	for partition := 0; partition < numPartitions; partition++ {
+		member := g.members[partition%len(g.members)]
+		g.assignment[member] = append(g.assignment[member], partition)
+	}
+}
+
+// snapshot returns a copy of all messages currently retained on the topic.
+func (b *Broker) snapshot(name string) []transport.Message {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, exist := b.topics[name]
+	if !exist {
+		return nil
+	}
+	t.expire(time.Now())
+
+	var messages []transport.Message
+	for _, partition := range t.partitions {
+		messages = append(messages, partition...)
+	}
+
+	return messages
+}
+
+// snapshotKey returns a copy of all messages with the key retained on the partition of the key.
+func (b *Broker) snapshotKey(name string, key string) []transport.Message {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	t, exist := b.topics[name]
+	if !exist {
+		return nil
+	}
+	t.expire(time.Now())
+
+	var messages []transport.Message
+	for _, msg := range t.partitions[t.partition(key)] {
+		if msg.Key == key {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	basic "github.com/mwantia/asynk/internal/log"
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+var _ transport.Transport = (*Client)(nil)
+
+type Client struct {
+	mutex    sync.RWMutex
+	logger   log.LogWrapper
+	options  options.ClientOptions
+	broker   *Broker
+	cleanups []func() error
+}
+
+func NewMemory(broker *Broker, opts ...options.ClientOption) (*Client, error) {
+	if broker == nil {
+		return nil, errors.New("broker cannot be nil")
+	}
+
+	options := options.DefaultClientOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return nil, err
+		}
+	}
+
+	var logger log.LogWrapper
+
+	if options.Logger != nil {
+		logger = basic.NewNamed(*options.Logger, "memory/client")
+	}
+	if logger == nil {
+		l := basic.NewBasic(options.LogLevel)
+		logger = l.Named("memory/client")
+	}
+
+	return &Client{
+		logger:  logger,
+		options: options,
+		broker:  broker,
+	}, nil
+}
+
+func (c *Client) Session(suffix string) (transport.Session, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.logger.Info("Creating new session with suffix '%s'", suffix)
+
+	return &Session{
+		ID:      event.UUIDv7(),
+		Suffix:  suffix,
+		client:  c,
+		logger:  c.logger.Named("memory/session"),
+		readers: make(map[string]*Reader),
+		writers: make(map[string]*Writer),
+	}, nil
+}
+
+func (c *Client) Cleanup() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.cleanups) == 0 {
+		return nil
+	}
+
+	c.logger.Info("Performing memory client cleanup")
+
+	var errs []error
+
+	for i, cleanup := range c.cleanups {
+		c.logger.Debug("Executing cleanup '%d'", i)
+
+		if err := cleanup(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	c.cleanups = nil
+	return errors.Join(errs...)
+}
+
+func (c *Client) addCleanup(cleanup func() error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cleanups = append(c.cleanups, cleanup)
+}
+
+func (c *Client) fullTopic(suffix, topic string) string {
+	var text strings.Builder
+	if c.options.TopicPrefix != "" {
+		text.WriteString(c.options.TopicPrefix + ".")
+	}
+	if c.options.Pool != "" {
+		text.WriteString(c.options.Pool + ".")
+	}
+
+	text.WriteString(suffix)
+	if topic != "" {
+		text.WriteString("." + topic)
+	}
+
+	return text.String()
+}
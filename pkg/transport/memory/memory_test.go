@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+func newSession(t *testing.T, broker *Broker, opts ...options.ClientOption) transport.Session {
+	t.Helper()
+
+	opts = append([]options.ClientOption{options.WithLogLevel("ERROR")}, opts...)
+
+	c, err := NewMemory(broker, opts...)
+	if err != nil {
+		t.Fatalf("failed to create memory client: %v", err)
+	}
+	t.Cleanup(func() { c.Cleanup() })
+
+	s, err := c.Session("test")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	return s
+}
+
+func write(t *testing.T, s transport.Session, topic string, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		if err := s.GetWriter(topic).WriteEvent(context.Background(), &event.StatusEvent{ID: id}); err != nil {
+			t.Fatalf("failed to write event '%s': %v", id, err)
+		}
+	}
+}
+
+func fetch(t *testing.T, r transport.Reader) (transport.Message, *event.StatusEvent) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ev := &event.StatusEvent{}
+	msg, err := r.FetchEvent(ctx, ev)
+	if err != nil {
+		t.Fatalf("failed to fetch event: %v", err)
+	}
+	return msg, ev
+}
+
+func TestGroupRedeliversUncommittedMessages(t *testing.T) {
+	broker := NewBroker()
+	writer := newSession(t, broker)
+	write(t, writer, "events", "a", "b", "c")
+
+	first := newSession(t, broker, options.WithGroupID("workers"))
+	reader := first.GetReader("events")
+
+	msg, ev := fetch(t, reader)
+	if ev.ID != "a" {
+		t.Fatalf("expected event 'a', got '%s'", ev.ID)
+	}
+	if err := reader.Commit(context.Background(), msg); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// Fetched but never committed, as if the worker has been killed
+	fetch(t, reader)
+	reader.(*Reader).Close()
+
+	second := newSession(t, broker, options.WithGroupID("workers"))
+	_, ev = fetch(t, second.GetReader("events"))
+	if ev.ID != "b" {
+		t.Fatalf("expected uncommitted event 'b' to be delivered again, got '%s'", ev.ID)
+	}
+}
+
+func TestCreateTopicGrowsImplicitTopic(t *testing.T) {
+	broker := NewBroker()
+	s := newSession(t, broker)
+
+	// Writing first creates the topic implicitly with a single partition
+	write(t, s, "events", "before")
+
+	if err := s.CreateTopic(context.Background(), "events", options.WithNumPartitions(4)); err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+
+	ids := make([]string, 32)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("task-%d", i)
+	}
+	write(t, s, "events", ids...)
+
+	partitions := make(map[int]bool)
+	reader := s.GetReader("events", options.WithBroadcast())
+	for range len(ids) + 1 {
+		msg, _ := fetch(t, reader)
+		partitions[msg.Partition] = true
+	}
+
+	if len(partitions) != 4 {
+		t.Fatalf("expected messages on 4 partitions, got %d", len(partitions))
+	}
+}
+
+func TestDefaultPartitionsKeepKeysTogether(t *testing.T) {
+	broker := NewBroker(options.WithDefaultPartitions(3))
+	s := newSession(t, broker)
+
+	write(t, s, "events", "a", "b", "a", "c", "a")
+
+	partitions := make(map[string]map[int]bool)
+	for _, msg := range broker.snapshot(s.(*Session).client.fullTopic("test", "events")) {
+		if partitions[msg.Key] == nil {
+			partitions[msg.Key] = make(map[int]bool)
+		}
+		partitions[msg.Key][msg.Partition] = true
+	}
+
+	if len(partitions["a"]) != 1 {
+		t.Fatalf("expected all messages with key 'a' on a single partition, got %d", len(partitions["a"]))
+	}
+}
+
+func TestGroupSplitsPartitionsBetweenMembers(t *testing.T) {
+	broker := NewBroker(options.WithDefaultPartitions(2))
+	writer := newSession(t, broker)
+
+	first := newSession(t, broker, options.WithGroupID("workers")).GetReader("events")
+	second := newSession(t, broker, options.WithGroupID("workers")).GetReader("events")
+
+	ids := make([]string, 16)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("task-%d", i)
+	}
+	write(t, writer, "events", ids...)
+
+	seen := make(map[string]int)
+	for _, reader := range []transport.Reader{first, second} {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+			ev := &event.StatusEvent{}
+			_, err := reader.FetchEvent(ctx, ev)
+			cancel()
+
+			if err != nil {
+				break
+			}
+			seen[ev.ID]++
+		}
+	}
+
+	if len(seen) != len(ids) {
+		t.Fatalf("expected %d events, got %d", len(ids), len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Fatalf("expected event '%s' once, got %d", id, count)
+		}
+	}
+}
+
+func TestScanReturnsRetainedMessages(t *testing.T) {
+	broker := NewBroker()
+	s := newSession(t, broker)
+	write(t, s, "events", "a", "b")
+
+	var ids []string
+	if err := s.Scan(context.Background(), "events", func(msg transport.Message) error {
+		ev := &event.StatusEvent{}
+		if err := s.Decode(msg, ev); err != nil {
+			return err
+		}
+		ids = append(ids, ev.ID)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to scan topic: %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Fatalf("expected events [a b], got %v", ids)
+	}
+}
+
+func TestRetentionKeepsOffsetsStable(t *testing.T) {
+	broker := NewBroker()
+	s := newSession(t, broker)
+
+	if err := s.CreateTopic(context.Background(), "events", options.WithNumPartitions(1), options.WithRetentionTime(time.Millisecond*50)); err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	write(t, s, "events", "a", "b")
+
+	reader := newSession(t, broker, options.WithGroupID("workers")).GetReader("events")
+	msg, _ := fetch(t, reader)
+	if err := reader.Commit(context.Background(), msg); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	// Both messages have expired once the next message is written
+	time.Sleep(time.Millisecond * 100)
+	write(t, s, "events", "c")
+
+	msg, ev := fetch(t, reader)
+	if ev.ID != "c" || msg.Offset != 2 {
+		t.Fatalf("expected event 'c' at offset 2, got '%s' at offset %d", ev.ID, msg.Offset)
+	}
+
+	var ids []string
+	if err := s.Scan(context.Background(), "events", func(msg transport.Message) error {
+		ids = append(ids, msg.Key)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to scan topic: %v", err)
+	}
+	if len(ids) != 1 {
+		t.Fatalf("expected a single retained message, got %d", len(ids))
+	}
+}
+
+func TestScanKeyReturnsMessagesOfKey(t *testing.T) {
+	broker := NewBroker(options.WithDefaultPartitions(4))
+	s := newSession(t, broker)
+	write(t, s, "events", "a", "b", "a", "c")
+
+	count := 0
+	if err := s.ScanKey(context.Background(), "events", "a", func(msg transport.Message) error {
+		if msg.Key != "a" {
+			t.Fatalf("expected only messages with key 'a', got '%s'", msg.Key)
+		}
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to scan key: %v", err)
+	}
+
+	if count != 2 {
+		t.Fatalf("expected 2 messages with key 'a', got %d", count)
+	}
+}
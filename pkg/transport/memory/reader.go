@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+type Reader struct {
+	session *Session
+	logger  log.LogWrapper
+	topic   string
+	groupID string
+	latest  bool
+	closed  atomic.Bool
+
+	// Only accessed while holding the broker mutex
+	offsets []int
+	cursor  int
+}
+
+func (r *Reader) ReadEvent(ctx context.Context, ev event.Event) error {
+	msg, err := r.FetchEvent(ctx, ev)
+	if err != nil {
+		var derr *transport.DecodeError
+		if errors.As(err, &derr) {
+			r.session.client.broker.commit(r.topic, r, msg)
+		}
+		return err
+	}
+
+	return r.Commit(ctx, msg)
+}
+
+func (r *Reader) FetchEvent(ctx context.Context, ev event.Event) (transport.Message, error) {
+	r.logger.Debug("Fetching new memory event...")
+
+	broker := r.session.client.broker
+
+	for {
+		if r.closed.Load() {
+			return transport.Message{}, fmt.Errorf("reader has already been closed")
+		}
+
+		broker.mutex.Lock()
+		msg, ok := broker.next(r.topic, r)
+		notify := broker.notify
+		broker.mutex.Unlock()
+
+		if ok {
+			r.logger.Debug("New memory event fetched with key '%s'", msg.Key)
+
+			if err := r.session.Decode(msg, ev); err != nil {
+				return msg, &transport.DecodeError{
+					Message: msg,
+					Err:     err,
+				}
+			}
+			return msg, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return transport.Message{}, fmt.Errorf("failed to fetch memory message: %w", ctx.Err())
+
+		case <-notify:
+			// New message or group change; Try again
+		}
+	}
+}
+
+func (r *Reader) Commit(ctx context.Context, msgs ...transport.Message) error {
+	if r.closed.Load() {
+		return fmt.Errorf("reader has already been closed")
+	}
+
+	r.session.client.broker.commit(r.topic, r, msgs...)
+	return nil
+}
+
+func (r *Reader) Close() error {
+	if !r.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	r.session.client.broker.leave(r.topic, r)
+	return nil
+}
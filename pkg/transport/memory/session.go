@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/options"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+type Session struct {
+	ID     string
+	Suffix string
+
+	mutex  sync.RWMutex
+	client *Client
+	logger log.LogWrapper
+
+	readers map[string]*Reader
+	writers map[string]*Writer
+}
+
+func (s *Session) GetID() string {
+	return s.ID
+}
+
+func (s *Session) GetSuffix() string {
+	return s.Suffix
+}
+
+func (s *Session) CreateTopic(ctx context.Context, topic string, opts ...options.TopicOption) error {
+	options := options.DefaultTopicOptions()
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Creating new topic '%s'", topic)
+
+	broker := s.client.broker
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.createTopic(s.client.fullTopic(s.Suffix, topic), options.NumPartitions, options.RetentionTime)
+	return nil
+}
+
+func (s *Session) GetReader(topic string, opts ...options.ReaderOption) transport.Reader {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	options := options.ReaderOptions{
+		GroupID: s.client.options.GroupID,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	key := topic + "/" + options.GroupID
+	if options.Broadcast {
+		options.GroupID = ""
+		key = topic + "/broadcast"
+	}
+
+	if reader, exist := s.readers[key]; exist {
+		s.logger.Debug("Returning existing reader for topic '%s'", topic)
+		return reader
+	}
+
+	reader := &Reader{
+		session: s,
+		logger:  s.logger.Named("memory/reader"),
+		topic:   s.client.fullTopic(s.Suffix, topic),
+		groupID: options.GroupID,
+		latest:  options.Broadcast && options.Latest,
+	}
+
+	s.logger.Debug("Creating new reader for topic '%s'", topic)
+
+	s.client.broker.join(reader.topic, reader)
+
+	s.readers[key] = reader
+	s.client.addCleanup(reader.Close)
+
+	return reader
+}
+
+func (s *Session) GetWriter(topic string) transport.Writer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if writer, exist := s.writers[topic]; exist {
+		s.logger.Debug("Returning existing writer for topic '%s'", topic)
+		return writer
+	}
+
+	writer := &Writer{
+		session: s,
+		logger:  s.logger.Named("memory/writer"),
+		topic:   s.client.fullTopic(s.Suffix, topic),
+	}
+
+	s.logger.Debug("Creating new writer for topic '%s'", topic)
+
+	s.writers[topic] = writer
+	return writer
+}
+
+func (s *Session) Decode(msg transport.Message, ev event.Event) error {
+	return transport.Decode(s.client.options.Codecs, msg, ev)
+}
+
+func (s *Session) Scan(ctx context.Context, topic string, fn func(transport.Message) error) error {
+	for _, msg := range s.client.broker.snapshot(s.client.fullTopic(s.Suffix, topic)) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) ScanKey(ctx context.Context, topic string, key string, fn func(transport.Message) error) error {
+	for _, msg := range s.client.broker.snapshotKey(s.client.fullTopic(s.Suffix, topic), key) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
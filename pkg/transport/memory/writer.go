@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/log"
+	"github.com/mwantia/asynk/pkg/transport"
+)
+
+type Writer struct {
+	session *Session
+	logger  log.LogWrapper
+	topic   string
+}
+
+func (w *Writer) WriteEvent(ctx context.Context, ev event.Event) error {
+	w.logger.Debug("Writing new memory event...")
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	key := ev.GetID()
+	now := time.Now()
+
+	contentType := w.session.client.options.Codec
+
+	value, err := transport.Encode(w.session.client.options.Codecs, contentType, ev)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	w.session.client.broker.publish(w.topic, transport.Message{
+		Key:   key,
+		Value: value,
+		Headers: map[string]string{
+			"session_id": w.session.ID,
+			"timestamp":  now.Format("2006-01-02 15:04:05"),
+
+			transport.HeaderContentType: contentType,
+		},
+		Time: now,
+	})
+
+	w.logger.Debug("New memory event written with key '%s'", key)
+	return nil
+}
@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mwantia/asynk/pkg/event"
+	"github.com/mwantia/asynk/pkg/options"
+)
+
+// Transport is the broker backend used by clients and servers to exchange events.
+type Transport interface {
+	Session(suffix string) (Session, error)
+
+	Cleanup() error
+}
+
+// Session groups the readers and writers used for a single suffix.
+type Session interface {
+	GetID() string
+
+	GetSuffix() string
+
+	CreateTopic(ctx context.Context, topic string, opts ...options.TopicOption) error
+
+	GetReader(topic string, opts ...options.ReaderOption) Reader
+
+	GetWriter(topic string) Writer
+
+	// Scan iterates over all messages currently retained on the topic without
+	// affecting any consumer group and returns once the end has been reached.
+	Scan(ctx context.Context, topic string, fn func(Message) error) error
+
+	// ScanKey iterates over all messages with the key currently retained on the topic, but only
+	// reads the partition the key is written to. It returns once the end of the partition has been reached.
+	ScanKey(ctx context.Context, topic string, key string, fn func(Message) error) error
+
+	// Decode unmarshals the message into the event, using the codec of its content type.
+	Decode(msg Message, ev event.Event) error
+}
+
+type Reader interface {
+	// ReadEvent reads the next event and commits its offset immediately.
+	ReadEvent(ctx context.Context, ev event.Event) error
+
+	// FetchEvent reads the next event without committing its offset.
+	// The returned message must be passed to Commit once it has been processed.
+	FetchEvent(ctx context.Context, ev event.Event) (Message, error)
+
+	Commit(ctx context.Context, msgs ...Message) error
+}
+
+type Writer interface {
+	WriteEvent(ctx context.Context, ev event.Event) error
+}
+
+type Message struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       string
+	Value     []byte
+	Headers   map[string]string
+	Time      time.Time
+}
+
+// DecodeError is returned by readers when a message could not be unmarshalled into the event.
+type DecodeError struct {
+	Message Message
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode message with key '%s': %v", e.Message.Key, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}